@@ -16,11 +16,7 @@
 
 package client
 
-import (
-	"fmt"
-
-	"github.com/bytedance/sonic"
-)
+import "fmt"
 
 // RequestBuilder holds config data for bce request.
 // Some of fields are required and the others are optional.
@@ -157,7 +153,7 @@ func (b *RequestBuilder) buildBceRequest() (*BceRequest, error) {
 		req.SetParams(b.queryParams)
 	}
 	if b.body != nil {
-		bodyBytes, err := sonic.Marshal(b.body)
+		bodyBytes, err := MarshalJSON(b.client, b.body)
 		if err != nil {
 			return nil, err
 		}
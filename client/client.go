@@ -39,7 +39,7 @@ package client
 
 import (
 	"bytes"
-	"fmt"
+	"context"
 	"io"
 	"io/ioutil"
 	"strconv"
@@ -61,6 +61,10 @@ type Client interface {
 
 // BceClient defines the general client to access the BCE services.
 type BceClient struct {
+	// clockOffset is the seconds the server clock is ahead of the local clock, it is the first
+	// field to be 64-bit aligned for the atomic operations
+	clockOffset int64
+
 	Config *BceClientConfiguration
 	Signer auth.Signer // the sign algorithm
 }
@@ -85,10 +89,17 @@ func (c *BceClient) buildHTTPRequest(request *BceRequest) {
 	}
 	request.SetTimeout(c.Config.RequestTimeoutInMillis / 1000)
 
+	// Set the extra headers unless set by the request
+	for key, value := range c.Config.ExtraHeaders {
+		if request.Header(key) == "" {
+			request.SetHeader(key, value)
+		}
+	}
+
 	// Set the BCE request headers
 	request.SetHeader(http.Host, request.Host())
 	request.SetHeader(http.UserAgent, c.Config.UserAgent)
-	request.SetHeader(http.Date, util.FormatISO8601Date(util.NowUTCSeconds()))
+	c.setDate(request)
 	request.SetHeader(http.RequestTimeoutMS, strconv.Itoa(c.Config.RequestTimeoutInMillis))
 
 	//set default content-type if null
@@ -96,8 +107,10 @@ func (c *BceClient) buildHTTPRequest(request *BceRequest) {
 		request.SetHeader(http.ContentType, DefaultContentType)
 	}
 
-	// Generate the auth string if needed
-	if c.Config.Credentials != nil {
+	// Generate the auth string if needed, the credentials of the request take precedence
+	if credentials := request.Credentials(); credentials != nil {
+		c.Signer.Sign(&request.Request, credentials, c.Config.SignOption)
+	} else if c.Config.Credentials != nil {
 		c.Signer.Sign(&request.Request, c.Config.Credentials, c.Config.SignOption)
 	}
 }
@@ -116,6 +129,8 @@ func (c *BceClient) SendRequest(req *BceRequest, resp *BceResponse) error {
 	if req.ClientError() != nil {
 		return req.ClientError()
 	}
+	c.setContentMD5(req, req.content)
+	c.setCRC32C(req, req.content)
 
 	// Build the http request and prepare to send
 	c.buildHTTPRequest(req)
@@ -123,37 +138,45 @@ func (c *BceClient) SendRequest(req *BceRequest, resp *BceResponse) error {
 
 	// Send request with the given retry policy
 	retries := 0
+	redirects := 0
+	skewCorrected := false
 	if req.Body() != nil {
 		defer req.Body().Close() // Manually close the ReadCloser body for retry
 	}
 	for {
-		// The request body should be temporarily saved if retry to send the http request
+		// The stream body should be temporarily saved if retry or redirect to send the http
+		// request, it is buffered lazily only when a resend may occur and the body can not be
+		// rewound.
 		var retryBuf bytes.Buffer
 		var teeReader io.Reader
-		if c.Config.Retry.ShouldRetry(nil, 0) && req.Body() != nil {
+		if req.Body() != nil && req.content == nil &&
+			(c.Config.Retry.ShouldRetry(nil, retries) || !c.Config.RedirectDisabled) {
 			teeReader = io.TeeReader(req.Body(), &retryBuf)
 			req.Request.SetBody(ioutil.NopCloser(teeReader))
 		}
 		httpResp, err := http.Execute(&req.Request)
 
 		if err != nil {
-			if c.Config.Retry.ShouldRetry(err, retries) {
+			if req.Context().Err() == nil && c.Config.Retry.ShouldRetry(err, retries) {
 				delayInMills := c.Config.Retry.GetDelayBeforeNextRetryInMillis(err, retries)
-				time.Sleep(delayInMills)
+				if ctxErr := sleepContext(req.Context(), delayInMills); ctxErr != nil {
+					return canceledError(req, ctxErr, retries)
+				}
 			} else {
-				return &BceClientError{
-					fmt.Sprintf("execute http request failed! Retried %d times, error: %v",
-						retries, err)}
+				return NewBceClientErrorWithCause(err,
+					"execute http request %s %s failed! Retried %d times",
+					req.Method(), req.URI(), retries)
 			}
 			retries++
 			log.Warnf("send request failed: %v, retry for %d time(s)", err, retries)
-			if req.Body() != nil {
-				_, _ = io.ReadAll(teeReader)
-				req.Request.SetBody(ioutil.NopCloser(&retryBuf))
-			}
+			resetBody(req, teeReader, &retryBuf)
 			continue
 		}
 		resp.SetHTTPResponse(httpResp)
+		resp.SetMaxErrorBodySize(c.Config.MaxErrorBodySizeInBytes)
+		resp.SetJSONAPI(JSONAPI(c))
+		resp.SetVerifyMD5(c.Config.VerifyResponseMD5)
+		resp.SetVerifyCRC32C(c.Config.VerifyResponseCRC32C)
 		resp.ParseResponse()
 
 		log.Infof("receive http response: status: %s, debugId: %s, requestId: %s, elapsed: %v",
@@ -166,26 +189,91 @@ func (c *BceClient) SendRequest(req *BceRequest, resp *BceResponse) error {
 		for k, v := range resp.Headers() {
 			log.Debugf("%s=%s", k, v)
 		}
+		if !c.Config.RedirectDisabled && isRedirect(resp) {
+			if err := c.redirect(req, resp, redirects); err != nil {
+				return err
+			}
+			redirects++
+			resetBody(req, teeReader, &retryBuf)
+			continue
+		}
 		if resp.IsFail() {
 			err := resp.ServiceError()
+			if !skewCorrected && c.correctClockSkew(req, resp) {
+				// re-sign the request with the corrected time and retry immediately, unless the
+				// stream body is drained and can not be sent again
+				if !resetBody(req, teeReader, &retryBuf) {
+					return err
+				}
+				skewCorrected = true
+				c.buildHTTPRequest(req)
+				continue
+			}
 			if c.Config.Retry.ShouldRetry(err, retries) {
 				delayInMills := c.Config.Retry.GetDelayBeforeNextRetryInMillis(err, retries)
-				time.Sleep(delayInMills)
+				if ctxErr := sleepContext(req.Context(), delayInMills); ctxErr != nil {
+					return canceledError(req, ctxErr, retries)
+				}
 			} else {
 				return err
 			}
 			retries++
 			log.Warnf("send request failed, retry for %d time(s)", retries)
-			if req.Body() != nil {
-				_, _ = io.ReadAll(teeReader)
-				req.Request.SetBody(ioutil.NopCloser(&retryBuf))
-			}
+			resetBody(req, teeReader, &retryBuf)
 			continue
 		}
 		return nil
 	}
 }
 
+// setContentMD5 - add the Content-MD5 header of the body if enabled, the stream bodies are not
+// read ahead for it
+func (c *BceClient) setContentMD5(req *BceRequest, content []byte) {
+	if c.Config.SendRequestMD5 && len(content) != 0 && len(req.Header(http.ContentMD5)) == 0 {
+		req.SetHeader(http.ContentMD5, util.ContentMD5(content))
+	}
+}
+
+// setCRC32C - add the CRC32C checksum header of the body if it reaches the threshold
+func (c *BceClient) setCRC32C(req *BceRequest, content []byte) {
+	threshold := c.Config.CRC32CThresholdInBytes
+	if threshold > 0 && int64(len(content)) >= threshold {
+		req.SetHeader(http.BceContentCRC32C, util.ContentCRC32C(content))
+	}
+}
+
+// sleepContext - sleep for the delay before the next retry unless the context is done first
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// canceledError - the error of the request whose context is done while waiting to retry
+func canceledError(req *BceRequest, ctxErr error, retries int) error {
+	return NewBceClientErrorWithCause(ctxErr, "http request %s %s canceled! Retried %d times",
+		req.Method(), req.URI(), retries)
+}
+
+// resetBody - prepare the request body for the next retry, it returns false if the body is a
+// stream neither rewindable nor buffered
+func resetBody(req *BceRequest, teeReader io.Reader, retryBuf *bytes.Buffer) bool {
+	if req.Body() == nil || req.rewindBody() {
+		return true
+	}
+	if teeReader == nil {
+		return false
+	}
+	_, _ = io.ReadAll(teeReader)
+	req.Request.SetBody(ioutil.NopCloser(retryBuf))
+	return true
+}
+
 // SendRequestFromBytes - the client performs sending the http request with retry policy and receive the
 // response from the BCE services.
 //
@@ -201,42 +289,71 @@ func (c *BceClient) SendRequestFromBytes(req *BceRequest, resp *BceResponse, con
 	if req.ClientError() != nil {
 		return req.ClientError()
 	}
+	c.setContentMD5(req, content)
+	c.setCRC32C(req, content)
 	// Build the http request and prepare to send
 	c.buildHTTPRequest(req)
 	log.Infof("send http request: %v", req)
 	// Send request with the given retry policy
 	retries := 0
+	redirects := 0
+	skewCorrected := false
+	// The content is kept on the request so that a 303 redirect can drop it
+	req.content = content
 	for {
-		// The request body should be temporarily saved if retry to send the http request
-		buf := bytes.NewBuffer(content)
-		req.Request.SetBody(ioutil.NopCloser(buf))
-		defer req.Request.Body().Close() // Manually close the ReadCloser body for retry
+		// The body is rebuilt from the content for every retry and redirect
+		if req.content != nil {
+			req.Request.SetBody(ioutil.NopCloser(bytes.NewReader(req.content)))
+		} else {
+			req.Request.SetBody(nil)
+		}
 		httpResp, err := http.Execute(&req.Request)
 		if err != nil {
-			if c.Config.Retry.ShouldRetry(err, retries) {
+			if req.Context().Err() == nil && c.Config.Retry.ShouldRetry(err, retries) {
 				delayInMills := c.Config.Retry.GetDelayBeforeNextRetryInMillis(err, retries)
-				time.Sleep(delayInMills)
+				if ctxErr := sleepContext(req.Context(), delayInMills); ctxErr != nil {
+					return canceledError(req, ctxErr, retries)
+				}
 			} else {
-				return &BceClientError{
-					fmt.Sprintf("execute http request failed! Retried %d times, error: %v",
-						retries, err)}
+				return NewBceClientErrorWithCause(err,
+					"execute http request %s %s failed! Retried %d times",
+					req.Method(), req.URI(), retries)
 			}
 			retries++
 			log.Warnf("send request failed: %v, retry for %d time(s)", err, retries)
 			continue
 		}
 		resp.SetHTTPResponse(httpResp)
+		resp.SetMaxErrorBodySize(c.Config.MaxErrorBodySizeInBytes)
+		resp.SetJSONAPI(JSONAPI(c))
+		resp.SetVerifyMD5(c.Config.VerifyResponseMD5)
+		resp.SetVerifyCRC32C(c.Config.VerifyResponseCRC32C)
 		resp.ParseResponse()
 		log.Infof("receive http response: status: %s, debugId: %s, requestId: %s, elapsed: %v",
 			resp.StatusText(), resp.DebugID(), resp.RequestID(), resp.ElapsedTime())
 		for k, v := range resp.Headers() {
 			log.Debugf("%s=%s", k, v)
 		}
+		if !c.Config.RedirectDisabled && isRedirect(resp) {
+			if err := c.redirect(req, resp, redirects); err != nil {
+				return err
+			}
+			redirects++
+			continue
+		}
 		if resp.IsFail() {
 			err := resp.ServiceError()
+			if !skewCorrected && c.correctClockSkew(req, resp) {
+				// re-sign the request with the corrected time and retry immediately
+				skewCorrected = true
+				c.buildHTTPRequest(req)
+				continue
+			}
 			if c.Config.Retry.ShouldRetry(err, retries) {
 				delayInMills := c.Config.Retry.GetDelayBeforeNextRetryInMillis(err, retries)
-				time.Sleep(delayInMills)
+				if ctxErr := sleepContext(req.Context(), delayInMills); ctxErr != nil {
+					return canceledError(req, ctxErr, retries)
+				}
 			} else {
 				return err
 			}
@@ -248,6 +365,27 @@ func (c *BceClient) SendRequestFromBytes(req *BceRequest, resp *BceResponse, con
 	}
 }
 
+// Warmup - pre-establish n connections(TCP and TLS handshakes) to the endpoint so that the first
+// burst of requests does not pay the connection setup latency. The warm-up requests are unsigned
+// HEAD requests of the root path whose responses, e.g. 401 or 404, are ignored, only the
+// connections count. The service clients should send signed harmless requests instead, e.g.
+// mochow.Client.Warmup lists the databases.
+//
+// PARAMS:
+//   - ctx: the context to cancel the warm-up
+//   - n: the number of connections
+//
+// RETURNS:
+//   - error: nil if ok otherwise the specific error
+func (c *BceClient) Warmup(ctx context.Context, n int) error {
+	req := &http.Request{}
+	req.SetEndpoint(c.Config.Endpoint)
+	if err := http.Warmup(ctx, req.Endpoint()+"/", n); err != nil {
+		return NewBceClientErrorWithCause(err, "warm up %d connections to %s failed", n, req.Endpoint())
+	}
+	return nil
+}
+
 func (c *BceClient) GetBceClientConfig() *BceClientConfiguration {
 	return c.Config
 }
@@ -256,9 +394,12 @@ func NewBceClient(conf *BceClientConfiguration, sign auth.Signer) *BceClient {
 	clientConfig := http.ClientConfig{
 		RedirectDisabled:         conf.RedirectDisabled,
 		ConnectionTimeoutInMills: conf.ConnectionTimeoutInMillis,
+		KeepAliveInMills:         conf.KeepAliveInMillis,
+		SmallIntervalInMills:     conf.SmallIntervalInMillis,
+		LargeIntervalInMills:     conf.LargeIntervalInMillis,
 	}
 	http.InitClient(clientConfig)
-	return &BceClient{conf, sign}
+	return &BceClient{Config: conf, Signer: sign}
 }
 
 func NewBceClientWithAPIKey(account, apiKey, endPoint string) (*BceClient, error) {
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// codec.go - defines the tunable json codec used to marshal requests and unmarshal responses
+
+package client
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/bytedance/sonic"
+	"github.com/bytedance/sonic/option"
+)
+
+// JSONOptions defines the tuning options of the sonic codec used by the client. The zero value
+// is the same as the sonic default config, which does not sort map keys and decodes numbers
+// into float64.
+type JSONOptions struct {
+	// SortMapKeys sorts the keys of maps when marshaling, it costs more CPU for large batches
+	SortMapKeys bool
+	// EscapeHTML escapes the HTML characters <, > and & in the marshaled strings
+	EscapeHTML bool
+	// UseNumber decodes numbers into json.Number instead of float64
+	UseNumber bool
+	// UseInt64 decodes integers into int64 instead of float64
+	UseInt64 bool
+	// CopyString copies the decoded strings instead of referring to the response buffer
+	CopyString bool
+	// Pretouch compiles the codec of the hot request and response structs when the client is
+	// created, so that the first requests do not pay the JIT compiling latency
+	Pretouch bool
+}
+
+var frozenJSONAPIs sync.Map // JSONOptions -> sonic.API
+
+// API returns the frozen sonic API of the options, it is cached and safe for concurrent use.
+func (o JSONOptions) API() sonic.API {
+	if api, ok := frozenJSONAPIs.Load(o); ok {
+		return api.(sonic.API)
+	}
+	api, _ := frozenJSONAPIs.LoadOrStore(o, sonic.Config{
+		SortMapKeys: o.SortMapKeys,
+		EscapeHTML:  o.EscapeHTML,
+		UseNumber:   o.UseNumber,
+		UseInt64:    o.UseInt64,
+		CopyString:  o.CopyString,
+	}.Froze())
+	return api.(sonic.API)
+}
+
+// JSONAPI returns the sonic API configured by the client, sonic.ConfigDefault if not set.
+func JSONAPI(cli Client) sonic.API {
+	if cli == nil {
+		return sonic.ConfigDefault
+	}
+	if conf := cli.GetBceClientConfig(); conf != nil && conf.JSONOptions != nil {
+		return conf.JSONOptions.API()
+	}
+	return sonic.ConfigDefault
+}
+
+// MarshalJSON - marshal the value with the json codec configured by the client
+//
+// PARAMS:
+//   - cli: the client whose configuration decides the codec options
+//   - v: the value to be marshaled
+//
+// RETURNS:
+//   - []byte: the marshaled json bytes
+//   - error: nil if ok otherwise the specific error
+func MarshalJSON(cli Client, v interface{}) ([]byte, error) {
+	return JSONAPI(cli).Marshal(v)
+}
+
+// Pretouch - compile the codec of the given types in advance to avoid the JIT compiling latency
+// of the first requests.
+//
+// PARAMS:
+//   - types: the types of the hot structs
+//
+// RETURNS:
+//   - error: nil if ok otherwise the specific error
+func Pretouch(types ...reflect.Type) error {
+	for _, t := range types {
+		if err := sonic.Pretouch(t, option.WithCompileRecursiveDepth(2)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
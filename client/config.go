@@ -33,6 +33,9 @@ const (
 	DefaultConnectionTimeoutInMills = 10 * 1000
 	DefaultRequestTimeoutInMills    = 60 * 1000
 	DefaultWarnLogTimeoutInMills    = 5 * 1000
+	DefaultMaxErrorBodySizeInBytes  = 1 << 20
+	DefaultMaxPayloadSizeInBytes    = 32 << 20
+	DefaultMaxRedirects             = 10
 )
 
 var (
@@ -59,10 +62,49 @@ type BceClientConfiguration struct {
 	Retry                     RetryPolicy
 	ConnectionTimeoutInMillis int
 	RequestTimeoutInMillis    int
+	// KeepAliveInMillis is the TCP keep-alive period of the connections, use the default of the
+	// http package if zero and disable the keep-alive probes if negative
+	KeepAliveInMillis int
+	// SmallIntervalInMillis and LargeIntervalInMillis are the read and write deadlines of the
+	// ongoing io operations and the idle connections, use the defaults of the http package if
+	// not positive
+	SmallIntervalInMillis int
+	LargeIntervalInMillis int
 	// CnameEnabled should be true when use custom domain as endpoint to visit bos resource
-	CnameEnabled     bool
-	BackupEndpoint   string
+	CnameEnabled   bool
+	BackupEndpoint string
+	// RedirectDisabled returns the redirect responses as is, otherwise the redirects to the same
+	// host with the same or a stronger scheme are followed with the requests re-signed, and the
+	// other redirects are returned as errors
 	RedirectDisabled bool
+	// MaxRedirects caps the number of the redirects followed by one request, use
+	// DefaultMaxRedirects if zero
+	MaxRedirects int
+	// MaxErrorBodySizeInBytes limits the size of the error body to be read from a failed response,
+	// the remainder is discarded, use DefaultMaxErrorBodySizeInBytes if not positive
+	MaxErrorBodySizeInBytes int64
+	// JSONOptions tunes the json codec of the request and response body, use the sonic default
+	// config if nil
+	JSONOptions *JSONOptions
+	// MaxPayloadSizeInBytes is the max body size of one request, the larger row and batch
+	// search requests are split automatically, use DefaultMaxPayloadSizeInBytes if zero and
+	// disable the splitting if negative
+	MaxPayloadSizeInBytes int64
+	// ExtraHeaders are added to all the requests before signing, e.g. the gateway routing headers,
+	// the headers set on the request and the standard headers of the SDK take precedence
+	ExtraHeaders map[string]string
+	// SendRequestMD5 adds the Content-MD5 header of the request bodies kept in memory, e.g. the
+	// upserts, so that the server can verify them, the stream bodies are sent without it
+	SendRequestMD5 bool
+	// VerifyResponseMD5 verifies the body of the response against its Content-MD5 header if any,
+	// to detect the corruption through the proxies
+	VerifyResponseMD5 bool
+	// CRC32CThresholdInBytes adds the CRC32C checksum header to the request bodies not smaller
+	// than it, e.g. the large upserts, so that the server can verify them, disabled if not positive
+	CRC32CThresholdInBytes int64
+	// VerifyResponseCRC32C verifies the body of the response against its CRC32C checksum header if
+	// any, e.g. the rows of the exports
+	VerifyResponseCRC32C bool
 }
 
 func (c *BceClientConfiguration) String() string {
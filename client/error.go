@@ -16,7 +16,12 @@
 
 package client
 
-import "strconv"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
 
 const (
 	accessDenied          = "AccessDenied"
@@ -35,12 +40,29 @@ type BceError interface {
 	error
 }
 
-// BceClientError defines the error struct for the client when making request
-type BceClientError struct{ Message string }
+// BceClientError defines the error struct for the client when making request, the underlying
+// error(e.g. the net error of the transport) is kept so that `errors.Is' and `errors.As' work.
+type BceClientError struct {
+	Message string
+	Err     error
+}
 
 func (b *BceClientError) Error() string { return b.Message }
 
-func NewBceClientError(msg string) *BceClientError { return &BceClientError{msg} }
+func (b *BceClientError) Unwrap() error { return b.Err }
+
+// Timeout reports whether the underlying error is a timeout, it makes `os.IsTimeout' work.
+func (b *BceClientError) Timeout() bool {
+	var timeoutErr interface{ Timeout() bool }
+	return errors.As(b.Err, &timeoutErr) && timeoutErr.Timeout()
+}
+
+func NewBceClientError(msg string) *BceClientError { return &BceClientError{Message: msg} }
+
+// NewBceClientErrorWithCause - build a client error wrapping the given cause
+func NewBceClientErrorWithCause(err error, format string, args ...interface{}) *BceClientError {
+	return &BceClientError{Message: fmt.Errorf(format+": %w", append(args, err)...).Error(), Err: err}
+}
 
 // BceServiceError defines the error struct for the BCE service when receiving response
 type BceServiceError struct {
@@ -48,15 +70,65 @@ type BceServiceError struct {
 	Message    string `json:"msg"`
 	RequestID  string
 	StatusCode int
+
+	// RawBody and ContentType are only filled when the error body can not be decoded, the
+	// RawBody is truncated to at most MaxRawBodySnippetSize bytes for diagnosis.
+	RawBody     string `json:"-"`
+	ContentType string `json:"-"`
+
+	// Details keeps the extra fields of the error body besides the code and message, such as
+	// the index of the offending row or the field name, numbers are kept as json.Number.
+	Details map[string]interface{} `json:"-"`
+}
+
+// Detail returns the extra field of the error body with the given key
+func (b *BceServiceError) Detail(key string) (interface{}, bool) {
+	val, ok := b.Details[key]
+	return val, ok
 }
 
 func (b *BceServiceError) Error() string {
 	ret := "[Code: " + strconv.Itoa(b.Code)
 	ret += "; Message: " + b.Message
-	ret += "; RequestId: " + b.RequestID + "]"
+	ret += "; RequestId: " + b.RequestID
+	if len(b.RawBody) != 0 {
+		ret += "; ContentType: " + b.ContentType
+		ret += "; RawBody: " + b.RawBody
+	}
+	ret += "]"
 	return ret
 }
 
 func NewBceServiceError(code int, msg, reqID string, status int) *BceServiceError {
-	return &BceServiceError{code, msg, reqID, status}
+	return &BceServiceError{Code: code, Message: msg, RequestID: reqID, StatusCode: status}
+}
+
+// MaxRawBodySnippetSize is the max size of the raw body kept in the BceServiceError.
+const MaxRawBodySnippetSize = 512
+
+func rawBodySnippet(rawBody []byte) string {
+	if len(rawBody) <= MaxRawBodySnippetSize {
+		return string(rawBody)
+	}
+	return string(rawBody[:MaxRawBodySnippetSize]) + "...(truncated)"
+}
+
+// errorMessageKeys are the field names of the error message in the order of precedence
+var errorMessageKeys = []string{"msg", "message", "errorMessage", "error"}
+
+// parseErrorCode - parse the error code which may be a json number or a numeric string
+func parseErrorCode(code interface{}) (int, bool) {
+	switch val := code.(type) {
+	case json.Number:
+		if n, err := val.Int64(); err == nil {
+			return int(n), true
+		}
+	case float64:
+		return int(val), true
+	case string:
+		if n, err := strconv.Atoi(val); err == nil {
+			return n, true
+		}
+	}
+	return 0, false
 }
@@ -0,0 +1,62 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// pool.go - the object pools of the request and response to reduce allocations
+
+package client
+
+import "sync"
+
+var (
+	requestPool  = sync.Pool{New: func() interface{} { return &BceRequest{} }}
+	responsePool = sync.Pool{New: func() interface{} { return &BceResponse{} }}
+)
+
+// AcquireRequest returns an empty BceRequest from the pool, the header and param maps of the
+// pooled request are reused. It should be released by ReleaseRequest once no longer used.
+func AcquireRequest() *BceRequest {
+	return requestPool.Get().(*BceRequest)
+}
+
+// ReleaseRequest resets the request and puts it back to the pool, the request must not be
+// accessed after released.
+func ReleaseRequest(req *BceRequest) {
+	if req == nil {
+		return
+	}
+	req.Request.Reset()
+	req.requestID = ""
+	req.clientError = nil
+	req.content = nil
+	req.credentials = nil
+	req.clockOffset = 0
+	requestPool.Put(req)
+}
+
+// AcquireResponse returns an empty BceResponse from the pool. It should be released by
+// ReleaseResponse once the body is closed and no longer used, the ServiceError returned by the
+// response is still valid after released.
+func AcquireResponse() *BceResponse {
+	return responsePool.Get().(*BceResponse)
+}
+
+// ReleaseResponse resets the response and puts it back to the pool, the response must not be
+// accessed after released.
+func ReleaseResponse(resp *BceResponse) {
+	if resp == nil {
+		return
+	}
+	*resp = BceResponse{}
+	responsePool.Put(resp)
+}
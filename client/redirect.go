@@ -0,0 +1,112 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// redirect.go - follow the redirects of the BCE services with the re-signed requests
+
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/baidu/mochow-sdk-go/http"
+	"github.com/baidu/mochow-sdk-go/util/log"
+)
+
+// isRedirect reports whether the response redirects the request to the Location
+func isRedirect(resp *BceResponse) bool {
+	switch resp.StatusCode() {
+	case 301, 302, 303, 307, 308:
+		return len(resp.Header(http.Location)) != 0
+	}
+	return false
+}
+
+// redirect - point the request at the Location of the redirect response and re-sign it. The method
+// and body are kept except for 303, which switches to a bodiless GET. Only the redirects to the
+// same host with the same or a stronger scheme are followed, so that the signature made with the
+// credentials is never sent to another host or in plain text, the others are returned as the
+// service errors of the redirect status.
+//
+// PARAMS:
+//   - req: the request to be redirected
+//   - resp: the redirect response, its body is closed
+//   - hops: the number of the redirects followed so far
+//
+// RETURNS:
+//   - error: nil if ok otherwise the specific error
+func (c *BceClient) redirect(req *BceRequest, resp *BceResponse, hops int) error {
+	if resp.Body() != nil {
+		resp.Body().Close()
+	}
+	maxRedirects := c.Config.MaxRedirects
+	if maxRedirects == 0 {
+		maxRedirects = DefaultMaxRedirects
+	}
+	if hops >= maxRedirects {
+		return NewBceClientError(fmt.Sprintf("request %s %s stopped after %d redirects",
+			req.Method(), req.URI(), hops))
+	}
+
+	current := &url.URL{
+		Scheme:   req.Protocol(),
+		Host:     req.Host(),
+		Path:     req.URI(),
+		RawQuery: req.QueryString(),
+	}
+	location, err := current.Parse(resp.Header(http.Location))
+	if err != nil {
+		return NewBceClientErrorWithCause(err, "invalid redirect location %q",
+			resp.Header(http.Location))
+	}
+	if !sameOrigin(current, location) {
+		return NewBceServiceError(0, fmt.Sprintf("redirect to %s is not followed", location),
+			resp.RequestID(), resp.StatusCode())
+	}
+	log.Infof("redirect %s %s to %s", req.Method(), current, location)
+
+	req.SetProtocol(location.Scheme)
+	req.SetPort(0)
+	req.SetHost(location.Host)
+	req.SetURI(location.Path)
+	params := make(map[string]string)
+	for key, values := range location.Query() {
+		params[key] = values[0]
+	}
+	req.SetParams(params)
+	if resp.StatusCode() == 303 && req.Method() != http.Get && req.Method() != http.Head {
+		req.SetMethod(http.Get)
+		req.Request.SetBody(nil)
+		req.SetLength(0)
+		req.content = nil
+		delete(req.Headers(), http.ContentLength)
+		delete(req.Headers(), http.ContentMD5)
+		delete(req.Headers(), http.BceContentCRC32C)
+	}
+
+	// Reset the host dependent headers and the authorization for the new target
+	c.buildHTTPRequest(req)
+	return nil
+}
+
+// sameOrigin reports whether the location is on the same host as the current url and uses the
+// same scheme or upgrades http to https
+func sameOrigin(current, location *url.URL) bool {
+	if !strings.EqualFold(current.Hostname(), location.Hostname()) {
+		return false
+	}
+	from, to := strings.ToLower(current.Scheme), strings.ToLower(location.Scheme)
+	return from == to || from == "http" && to == "https"
+}
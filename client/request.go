@@ -23,6 +23,7 @@ import (
 	"io/ioutil"
 	"os"
 
+	"github.com/baidu/mochow-sdk-go/auth"
 	"github.com/baidu/mochow-sdk-go/http"
 	"github.com/baidu/mochow-sdk-go/util"
 )
@@ -34,6 +35,10 @@ import (
 type Body struct {
 	stream io.ReadCloser
 	size   int64
+
+	// content keeps the original bytes of the body so that it can be replayed for retrying
+	// without buffering the stream again
+	content []byte
 }
 
 func (b *Body) Stream() io.ReadCloser { return b.stream }
@@ -54,8 +59,7 @@ func (b *Body) Size() int64 { return b.size }
 func NewBodyFromBytes(stream []byte) (*Body, error) {
 	buf := bytes.NewBuffer(stream)
 	size := int64(buf.Len())
-	buf = bytes.NewBuffer(stream)
-	return &Body{ioutil.NopCloser(buf), size}, nil
+	return &Body{stream: ioutil.NopCloser(buf), size: size, content: stream}, nil
 }
 
 // NewBodyFromString - build a Body object from the string to be used in the http request, it
@@ -68,10 +72,7 @@ func NewBodyFromBytes(stream []byte) (*Body, error) {
 //   - *Body: the return Body object
 //   - error: error if any specific error occurs
 func NewBodyFromString(str string) (*Body, error) {
-	buf := bytes.NewBufferString(str)
-	size := int64(len(str))
-	buf = bytes.NewBufferString(str)
-	return &Body{ioutil.NopCloser(buf), size}, nil
+	return NewBodyFromBytes([]byte(str))
 }
 
 // NewBodyFromFile - build a Body object from the given file name to be used in the http request,
@@ -95,7 +96,7 @@ func NewBodyFromFile(fname string) (*Body, error) {
 	if _, err = file.Seek(0, 0); err != nil {
 		return nil, err
 	}
-	return &Body{file, fileInfo.Size()}, nil
+	return &Body{stream: file, size: fileInfo.Size()}, nil
 }
 
 // NewBodyFromSectionFile - build a Body object from the given file pointer with offset and size.
@@ -117,7 +118,7 @@ func NewBodyFromSectionFile(file *os.File, off, size int64) (*Body, error) {
 		return nil, err
 	}
 	section := io.NewSectionReader(file, off, size)
-	return &Body{ioutil.NopCloser(section), size}, nil
+	return &Body{stream: ioutil.NopCloser(section), size: size}, nil
 }
 
 // NewBodyFromSizedReader - build a Body object from the given reader with size.
@@ -151,8 +152,9 @@ func NewBodyFromSizedReader(r io.Reader, size int64) (*Body, error) {
 		}
 	}
 	body := &Body{
-		stream: ioutil.NopCloser(&buffer),
-		size:   rlen,
+		stream:  ioutil.NopCloser(&buffer),
+		size:    rlen,
+		content: buffer.Bytes(),
 	}
 	return body, nil
 }
@@ -162,6 +164,10 @@ type BceRequest struct {
 	http.Request
 	requestID   string
 	clientError *BceClientError
+	content     []byte
+	credentials *auth.BceCredentials
+	// clockOffset is the clock offset to the server the request is signed with
+	clockOffset int64
 }
 
 func (b *BceRequest) RequestID() string { return b.requestID }
@@ -172,14 +178,33 @@ func (b *BceRequest) ClientError() *BceClientError { return b.clientError }
 
 func (b *BceRequest) SetClientError(err *BceClientError) { b.clientError = err }
 
+// Credentials returns the credentials to sign the request, nil means the client credentials
+func (b *BceRequest) Credentials() *auth.BceCredentials { return b.credentials }
+
+// SetCredentials - sign the request with the credentials instead of the client credentials
+func (b *BceRequest) SetCredentials(credentials *auth.BceCredentials) { b.credentials = credentials }
+
+// Content returns the bytes of the body set by SetBody, nil if the body is a stream
+func (b *BceRequest) Content() []byte { return b.content }
+
 func (b *BceRequest) SetBody(body *Body) { // override SetBody derived from http.Request
 	b.Request.SetBody(body.Stream())
+	b.content = body.content
 	b.SetLength(body.Size()) // set field of "net/http.Request.ContentLength"
 	if body.Size() > 0 {
 		b.SetHeader(http.ContentLength, fmt.Sprintf("%d", body.Size()))
 	}
 }
 
+// rewindBody - replay the body from the original bytes, return false if the body is a stream
+func (b *BceRequest) rewindBody() bool {
+	if b.content == nil {
+		return false
+	}
+	b.Request.SetBody(ioutil.NopCloser(bytes.NewReader(b.content)))
+	return true
+}
+
 func (b *BceRequest) BuildHTTPRequest() {
 	// Only need to build the specific `requestId` field for BCE, other fields are same as the
 	// `http.Request` as well as its methods.
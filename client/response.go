@@ -17,12 +17,16 @@
 package client
 
 import (
+	"bytes"
+	"fmt"
 	"io"
 	"time"
 
+	"github.com/bytedance/sonic"
 	"github.com/bytedance/sonic/decoder"
 
 	"github.com/baidu/mochow-sdk-go/http"
+	"github.com/baidu/mochow-sdk-go/util"
 )
 
 // BceResponse defines the response structure for receiving BCE services response.
@@ -33,6 +37,30 @@ type BceResponse struct {
 	debugID      string
 	response     *http.Response
 	serviceError *BceServiceError
+
+	maxErrorBodySize int64
+	jsonAPI          sonic.API
+	raw              *RawResponse
+	metadata         *ResponseMetadata
+	verifyMD5        bool
+	verifyCRC32C     bool
+}
+
+// ResponseMetadata keeps the correlation ids of the response, so that they can be logged without
+// enabling the Info logging of the SDK.
+type ResponseMetadata struct {
+	RequestID   string
+	DebugID     string
+	StatusCode  int
+	ElapsedTime time.Duration
+}
+
+// RawResponse keeps the status, headers and raw body of the response, so that the fields not
+// modeled by the SDK can be accessed besides the parsed result.
+type RawResponse struct {
+	StatusCode int
+	Headers    map[string]string
+	Body       []byte
 }
 
 func (r *BceResponse) IsFail() bool {
@@ -79,32 +107,161 @@ func (r *BceResponse) ServiceError() *BceServiceError {
 	return r.serviceError
 }
 
+// SetMaxErrorBodySize - set the max size of the error body to be read when the response is failed
+func (r *BceResponse) SetMaxErrorBodySize(size int64) {
+	r.maxErrorBodySize = size
+}
+
+// SetJSONAPI - set the json codec used to parse the response body
+func (r *BceResponse) SetJSONAPI(api sonic.API) {
+	r.jsonAPI = api
+}
+
+// SetRawResponse - set the raw response to be filled when the response is parsed
+func (r *BceResponse) SetRawResponse(raw *RawResponse) {
+	r.raw = raw
+}
+
+// SetResponseMetadata - set the metadata to be filled when the response is parsed
+func (r *BceResponse) SetResponseMetadata(metadata *ResponseMetadata) {
+	r.metadata = metadata
+}
+
+// SetVerifyMD5 - verify the body against the Content-MD5 header of the response when parsing
+func (r *BceResponse) SetVerifyMD5(verify bool) {
+	r.verifyMD5 = verify
+}
+
+// SetVerifyCRC32C - verify the body against the CRC32C header of the response when parsing
+func (r *BceResponse) SetVerifyCRC32C(verify bool) {
+	r.verifyCRC32C = verify
+}
+
+// readErrorBody - read at most maxErrorBodySize bytes of the error body and discard a bounded
+// remainder so that the connection may be reused, a larger remainder is dropped with the body.
+func (r *BceResponse) readErrorBody() []byte {
+	limit := r.maxErrorBodySize
+	if limit <= 0 {
+		limit = DefaultMaxErrorBodySizeInBytes
+	}
+	rawBody, _ := io.ReadAll(io.LimitReader(r.Body(), limit))
+	_, _ = io.CopyN(io.Discard, r.Body(), limit)
+	return rawBody
+}
+
 func (r *BceResponse) ParseResponse() {
 	r.statusCode = r.response.StatusCode()
 	r.statusText = r.response.StatusText()
 	r.requestID = r.response.GetHeader(http.RequestID)
+	r.debugID = r.response.GetHeader(http.BceDebugID)
+	if r.metadata != nil {
+		*r.metadata = ResponseMetadata{
+			RequestID:   r.requestID,
+			DebugID:     r.debugID,
+			StatusCode:  r.statusCode,
+			ElapsedTime: r.response.ElapsedTime(),
+		}
+	}
+	if r.raw != nil {
+		r.raw.StatusCode = r.statusCode
+		r.raw.Headers = r.response.GetHeaders()
+		r.raw.Body = nil
+	}
 	if r.IsFail() {
 		r.serviceError = NewBceServiceError(-1, r.statusText, r.requestID, r.statusCode)
 
 		// First try to read the error `Code' and `Message' from body
-		rawBody, _ := io.ReadAll(r.Body())
+		rawBody := r.readErrorBody()
 		defer r.Body().Close()
-		if len(rawBody) != 0 {
-			jsonDecoder := decoder.NewDecoder(string(rawBody))
-			if err := jsonDecoder.Decode(r.serviceError); err != nil {
-				r.serviceError = NewBceServiceError(
-					-1,
-					"Service json error message decode failed",
-					r.requestID,
-					r.statusCode)
-			}
-			return
+		if r.raw != nil {
+			r.raw.Body = rawBody
 		}
+		r.decodeServiceError(rawBody)
+	}
+}
+
+// decodeServiceError - fill the service error from the error body, it is tolerant of the
+// alternate field names returned by some gateways as well as the plain-text error body.
+func (r *BceResponse) decodeServiceError(rawBody []byte) {
+	body := bytes.TrimSpace(rawBody)
+	if len(body) == 0 {
+		return
+	}
+	if body[0] != '{' {
+		r.serviceError.Message = rawBodySnippet(body)
+		return
+	}
+
+	payload := make(map[string]interface{})
+	jsonDecoder := decoder.NewDecoder(string(body))
+	jsonDecoder.UseNumber()
+	if err := jsonDecoder.Decode(&payload); err != nil {
+		r.serviceError = NewBceServiceError(
+			-1,
+			"Service json error message decode failed",
+			r.requestID,
+			r.statusCode)
+		r.serviceError.RawBody = rawBodySnippet(rawBody)
+		r.serviceError.ContentType = r.response.GetHeader(http.ContentType)
+		return
+	}
+	if code, ok := parseErrorCode(payload["code"]); ok {
+		r.serviceError.Code = code
+		delete(payload, "code")
+	}
+	for _, key := range errorMessageKeys {
+		if msg, ok := payload[key].(string); ok && len(msg) != 0 {
+			r.serviceError.Message = msg
+			delete(payload, key)
+			break
+		}
+	}
+	if len(payload) != 0 {
+		r.serviceError.Details = payload
 	}
 }
 
 func (r *BceResponse) ParseJSONBody(result interface{}) error {
 	defer r.Body().Close()
+	expectedMD5, expectedCRC32C := "", ""
+	if r.verifyMD5 {
+		expectedMD5 = r.Header(http.ContentMD5)
+	}
+	if r.verifyCRC32C {
+		expectedCRC32C = r.Header(http.BceContentCRC32C)
+	}
+	if r.raw != nil || len(expectedMD5) != 0 || len(expectedCRC32C) != 0 {
+		body, err := io.ReadAll(r.Body())
+		if err != nil {
+			return err
+		}
+		if r.raw != nil {
+			r.raw.Body = body
+		}
+		if len(expectedMD5) != 0 {
+			if actual := util.ContentMD5(body); actual != expectedMD5 {
+				return r.checksumMismatched("content-md5", expectedMD5, actual)
+			}
+		}
+		if len(expectedCRC32C) != 0 {
+			if actual := util.ContentCRC32C(body); actual != expectedCRC32C {
+				return r.checksumMismatched("crc32c", expectedCRC32C, actual)
+			}
+		}
+		if r.jsonAPI != nil {
+			return r.jsonAPI.Unmarshal(body, result)
+		}
+		return decoder.NewStreamDecoder(bytes.NewReader(body)).Decode(result)
+	}
+	if r.jsonAPI != nil {
+		return r.jsonAPI.NewDecoder(r.Body()).Decode(result)
+	}
 	jsonDecoder := decoder.NewStreamDecoder(r.Body())
 	return jsonDecoder.Decode(result)
 }
+
+func (r *BceResponse) checksumMismatched(kind, expected, actual string) error {
+	return NewBceClientError(fmt.Sprintf(
+		"%s of the response body mismatched, expected %s, actual %s, requestId: %s",
+		kind, expected, actual, r.requestID))
+}
@@ -17,6 +17,8 @@
 package client
 
 import (
+	"context"
+	"errors"
 	"net"
 	"net/http"
 	"time"
@@ -68,25 +70,10 @@ func (b *BackOffRetryPolicy) ShouldRetry(err BceError, attempts int) bool {
 		return true
 	}
 
-	// Always retry on IO error
-	if _, ok := err.(net.Error); ok {
+	if IsRetryable(err) {
+		log.Warnf("retry for retryable error: %v", err)
 		return true
 	}
-
-	// Only retry on a service error
-	if realErr, ok := err.(*BceServiceError); ok {
-		switch realErr.StatusCode {
-		case http.StatusInternalServerError:
-			log.Warn("retry for internal server error(500)")
-			return true
-		case http.StatusBadGateway:
-			log.Warn("retry for bad gateway(502)")
-			return true
-		case http.StatusServiceUnavailable:
-			log.Warn("retry for service unavailable(503)")
-			return true
-		}
-	}
 	return false
 }
 
@@ -105,3 +92,60 @@ func (b *BackOffRetryPolicy) GetDelayBeforeNextRetryInMillis(
 func NewBackOffRetryPolicy(maxRetry int, maxDelay, base int64) *BackOffRetryPolicy {
 	return &BackOffRetryPolicy{maxRetry, maxDelay, base}
 }
+
+// IsRetryable - check whether the error is worth retrying, it uses the same classification as the
+// internal BackOffRetryPolicy so that application level retry layers make consistent decisions.
+// The requests canceled by the caller are not retryable, while the transport timeouts are, and the
+// throttled requests are reported by IsThrottled instead since they should be retried only after
+// slowing down.
+//
+// PARAMS:
+//   - err: the error returned by the client
+//
+// RETURNS:
+//   - bool: true if the error is an IO error or a retryable service error
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// Never retry the requests canceled by the caller, the timeouts of the transport match
+	// context.DeadlineExceeded as well so that they are left to the net.Error check
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	// Always retry on IO error
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// Only retry on some of the service errors
+	var serviceErr *BceServiceError
+	if errors.As(err, &serviceErr) {
+		switch serviceErr.StatusCode {
+		case http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable:
+			return true
+		}
+	}
+	return false
+}
+
+// IsThrottled - check whether the error means the request is throttled by the service, the caller
+// should slow down before sending the next request.
+//
+// PARAMS:
+//   - err: the error returned by the client
+//
+// RETURNS:
+//   - bool: true if the service responds with too many requests(429)
+func IsThrottled(err error) bool {
+	var serviceErr *BceServiceError
+	if errors.As(err, &serviceErr) {
+		return serviceErr.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
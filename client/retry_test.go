@@ -0,0 +1,64 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIsRetryableTransportTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	httpClient := &http.Client{Timeout: 10 * time.Millisecond}
+	_, err := httpClient.Get(server.URL)
+	if err == nil {
+		t.Fatal("expected the request to time out")
+	}
+	if !IsRetryable(err) {
+		t.Errorf("transport timeout %v should be retryable", err)
+	}
+	if !IsRetryable(fmt.Errorf("send request: %w", err)) {
+		t.Errorf("wrapped transport timeout %v should be retryable", err)
+	}
+}
+
+func TestIsRetryableCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = http.DefaultClient.Do(req)
+	if err == nil {
+		t.Fatal("expected the request to be canceled")
+	}
+	if IsRetryable(err) {
+		t.Errorf("canceled request %v should not be retryable", err)
+	}
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// skew.go - the correction of the clock skew between the client and the server
+
+package client
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+
+	"github.com/baidu/mochow-sdk-go/http"
+	"github.com/baidu/mochow-sdk-go/util"
+	"github.com/baidu/mochow-sdk-go/util/log"
+)
+
+// IsRequestExpired reports whether the server rejects the request for the timestamp skew
+func IsRequestExpired(err error) bool {
+	var serviceErr *BceServiceError
+	if !errors.As(err, &serviceErr) {
+		return false
+	}
+	if code, _ := serviceErr.Detail("code"); code == requestExpired {
+		return true
+	}
+	msg := strings.ToLower(serviceErr.Message)
+	return strings.Contains(msg, "requestexpired") || strings.Contains(msg, "request expired") ||
+		strings.Contains(msg, "request has expired")
+}
+
+// setDate - set the Date header of the request by the current time corrected by the clock offset
+// to the server, the offset is recorded on the request
+func (c *BceClient) setDate(req *BceRequest) {
+	req.clockOffset = atomic.LoadInt64(&c.clockOffset)
+	req.SetHeader(http.Date, util.FormatISO8601Date(util.NowUTCSeconds()+req.clockOffset))
+}
+
+// correctClockSkew - update the clock offset by the Date header of the response rejected for the
+// timestamp skew, it returns true if the request is signed with another offset than the corrected
+// one, e.g. a stale offset already corrected by a concurrent request, so that it is worth re-signing
+func (c *BceClient) correctClockSkew(req *BceRequest, resp *BceResponse) bool {
+	if !IsRequestExpired(resp.ServiceError()) {
+		return false
+	}
+	serverTime, err := util.ParseRFC822Date(resp.Header(http.Date))
+	if err != nil {
+		return false
+	}
+	offset := serverTime.Unix() - util.NowUTCSeconds()
+	if atomic.SwapInt64(&c.clockOffset, offset) != offset {
+		log.Warnf("request expired for the clock skew, correct the clock offset to %d seconds",
+			offset)
+	}
+	return offset != req.clockOffset
+}
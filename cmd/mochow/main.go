@@ -0,0 +1,100 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// main.go - the command line tool of the Mochow SDK
+
+// Command mochow is the command line tool of the Mochow SDK.
+//
+// Usage:
+//
+//	mochow gen-struct -endpoint http://127.0.0.1:8511 -account root -database db -table book
+//
+// The api key is read from the MOCHOW_API_KEY environment variable if -apikey is not set.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/baidu/mochow-sdk-go/mochow"
+	"github.com/baidu/mochow-sdk-go/mochow/codegen"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: mochow <command> [flags]\n\nCommands:\n")
+	fmt.Fprintf(os.Stderr, "  gen-struct  generate the go struct of the rows from the table schema\n")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "gen-struct":
+		err = genStruct(os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		usage()
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func genStruct(args []string) error {
+	flags := flag.NewFlagSet("gen-struct", flag.ExitOnError)
+	endpoint := flags.String("endpoint", "", "the endpoint of the Mochow service")
+	account := flags.String("account", "root", "the account")
+	apiKey := flags.String("apikey", os.Getenv("MOCHOW_API_KEY"), "the api key")
+	database := flags.String("database", "", "the database name")
+	table := flags.String("table", "", "the table name")
+	pkg := flags.String("package", "", "the package name of the generated file")
+	typeName := flags.String("type", "", "the struct name, the camel case of the table by default")
+	jsonTags := flags.Bool("json", false, "add the json tags besides the mochow tags")
+	output := flags.String("o", "", "the output file, stdout by default")
+	flags.Parse(args)
+	if len(*database) == 0 || len(*table) == 0 {
+		return fmt.Errorf("-database and -table are required")
+	}
+
+	cli, err := mochow.NewClient(*account, *apiKey, *endpoint)
+	if err != nil {
+		return err
+	}
+	result, err := cli.DescTable(*database, *table)
+	if err != nil {
+		return err
+	}
+	src, err := codegen.GenerateStruct(result.Table, &codegen.Options{
+		Package:  *pkg,
+		TypeName: *typeName,
+		JSONTags: *jsonTags,
+	})
+	if err != nil {
+		return err
+	}
+	if len(*output) == 0 {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*output, src, 0644)
+}
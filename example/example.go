@@ -1,11 +1,10 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"time"
 
-	"github.com/baidu/mochow-sdk-go/client"
 	"github.com/baidu/mochow-sdk-go/mochow"
 	"github.com/baidu/mochow-sdk-go/mochow/api"
 )
@@ -26,51 +25,12 @@ func NewMochowTest(database, table string, config *mochow.ClientConfiguration) (
 }
 
 func (m *MochowTest) clearEnv() error {
-	// skip drop database and table when not existed
-	dbExisted, err := m.client.HasDatabase(m.database)
-	if err != nil {
-		log.Fatalf("Fail to check database due to error: %v", err)
-		return err
-	}
-	if !dbExisted {
-		return nil
-	}
-
-	// drop table first if existed
-	tableExisted, err := m.client.HasTable(m.database, m.table)
-	if err != nil {
-		log.Fatalf("Fail to check table due to error: %v", err)
+	// drop all the tables and the database, skip when not existed
+	log.Printf("Try to drop existed database: %s", m.database)
+	if err := m.client.DropDatabaseCascade(context.Background(), m.database); err != nil {
+		log.Fatalf("Fail to drop database due to error: %v", err)
 		return err
 	}
-	if tableExisted {
-		if err := m.client.DropTable(m.database, m.table); err != nil {
-			log.Printf("Fail to drop table due to error: %v", err)
-			return err
-		}
-		// wait util drop finished
-		for {
-			time.Sleep(time.Second * 5)
-			if _, err := m.client.DescTable(m.database, m.table); err != nil {
-				if realErr, ok := err.(*client.BceServiceError); ok {
-					if realErr.StatusCode == 404 || realErr.Code == int(api.TableNotExist) {
-						log.Println("Table already dropped")
-						break
-					}
-				}
-			}
-		}
-
-	}
-
-	// drop database if existed
-	if dbExisted {
-		log.Printf("Try to drop existed database: %s", m.database)
-		if err := m.client.DropDatabase(m.database); err != nil {
-			log.Fatalf("Fail to drop database due to error: %v", err)
-			return err
-		}
-	}
-
 	return nil
 }
 
@@ -160,14 +120,11 @@ func (m *MochowTest) createDatabaseAndTable() error {
 		log.Fatalf("Fail to create table due to error: %v", err)
 		return err
 	}
-	for {
-		time.Sleep(5)
-		describeTableResult, err := m.client.DescTable(m.database, m.table)
-		if err == nil && describeTableResult.Table.State == api.TableStateNormal {
-			log.Println("Table create finished")
-			break
-		}
+	if err := m.client.WaitForTableReady(context.Background(), m.database, m.table, nil); err != nil {
+		log.Fatalf("Fail to wait for table due to error: %v", err)
+		return err
 	}
+	log.Println("Table create finished")
 	return nil
 }
 
@@ -250,17 +207,13 @@ func (m *MochowTest) selectData() error {
 		Projections: []string{"id", "bookName"},
 		Limit:       1,
 	}
-	for {
-		selectResult, err := m.client.SelectRow(selectArgs)
-		if err != nil {
-			log.Fatalf("Fail to select row due to error: %v", err)
-			return err
-		}
-		log.Printf("Select row result: %+v", selectResult)
-		if !selectResult.IsTruncated {
-			break
-		}
-		selectArgs.Marker = selectResult.NextMarker
+	it := m.client.SelectAll(context.Background(), selectArgs)
+	for it.Next() {
+		log.Printf("Select row result: %+v", it.Row())
+	}
+	if err := it.Err(); err != nil {
+		log.Fatalf("Fail to select row due to error: %v", err)
+		return err
 	}
 	return nil
 }
@@ -292,14 +245,12 @@ func (m *MochowTest) searchData() error {
 		log.Fatalf("Fail to rebuild index due to error: %v", err)
 		return err
 	}
-	for {
-		time.Sleep(5)
-		descIndexResult, _ := m.client.DescIndex(m.database, m.table, "vector_idx")
-		if descIndexResult.Index.State == api.IndexStateNormal {
-			log.Println("Index rebuild finished")
-			break
-		}
+	err := m.client.WaitForIndexReady(context.Background(), m.database, m.table, "vector_idx", nil)
+	if err != nil {
+		log.Fatalf("Fail to wait for index due to error: %v", err)
+		return err
 	}
+	log.Println("Index rebuild finished")
 
 	// search
 	hnswParams := api.NewSearchParams()
@@ -378,41 +329,24 @@ func (m *MochowTest) deleteDataWithFilter() error {
 }
 
 func (m *MochowTest) dropAndCreateVIndex() error {
-	// drop vindex
-	if err := m.client.DropIndex(m.database, m.table, "vector_idx"); err != nil {
-		log.Fatalf("Fail to drop index due to error: %v", err)
-		return err
-	}
-	for {
-		time.Sleep(5)
-		_, err := m.client.DescIndex(m.database, m.table, "vector_idx")
-		if realErr, ok := err.(*client.BceServiceError); ok {
-			if realErr.Code == int(api.IndexNotExist) {
-				log.Printf("Index already dropped")
-				break
-			}
-		}
+	// drop, create and rebuild vector index, the previous index is restored if failed
+	index := api.IndexSchema{
+		IndexName:  "vector_idx",
+		Field:      "vector",
+		IndexType:  api.HNSW,
+		MetricType: api.L2,
+		Params: api.VectorIndexParams{
+			"M":              16,
+			"efConstruction": 200,
+		},
 	}
-
-	// create vector index
-	createIndexArgs := &api.CreateIndexArgs{
-		Database: m.database,
-		Table:    m.table,
-		Indexes: []api.IndexSchema{
-			{
-				IndexName:  "vector_idx",
-				Field:      "vector",
-				IndexType:  api.HNSW,
-				MetricType: api.L2,
-				Params: api.VectorIndexParams{
-					"M":              16,
-					"efConstruction": 200,
-				},
-			},
+	options := &mochow.ReindexOptions{
+		OnProgress: func(stage mochow.ReindexStage) {
+			log.Printf("Reindex stage: %s", stage)
 		},
 	}
-	if err := m.client.CreateIndex(createIndexArgs); err != nil {
-		log.Fatalf("Fail to create index due to error: %v", err)
+	if err := m.client.Reindex(context.Background(), m.database, m.table, index, options); err != nil {
+		log.Fatalf("Fail to reindex due to error: %v", err)
 		return err
 	}
 	return nil
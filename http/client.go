@@ -20,6 +20,9 @@
 package http
 
 import (
+	"context"
+	"errors"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
@@ -33,6 +36,8 @@ const (
 	DefaultDialTimeout           = 30 * time.Second
 	DefaultSmallInterval         = 600 * time.Second
 	DefaultLargeInterval         = 1200 * time.Second
+	DefaultKeepAlive             = 30 * time.Second
+	DefaultFallbackDelay         = 300 * time.Millisecond
 )
 
 // The httpClient is the global variable to send the request and get response
@@ -68,25 +73,64 @@ func (c *timeoutConn) SetReadDeadline(t time.Time) error  { return c.conn.SetRea
 func (c *timeoutConn) SetWriteDeadline(t time.Time) error { return c.conn.SetWriteDeadline(t) }
 
 type ClientConfig struct {
+	// RedirectDisabled is kept for compatibility, the redirects are never followed by the http
+	// client, the callers follow them explicitly so that the requests can be re-signed
 	RedirectDisabled         bool
 	ConnectionTimeoutInMills int
+	// KeepAliveInMills is the TCP keep-alive period of the connections, use DefaultKeepAlive if
+	// zero and disable the keep-alive probes if negative
+	KeepAliveInMills int
+	// SmallIntervalInMills is the read and write deadline of an ongoing io operation, use
+	// DefaultSmallInterval if not positive
+	SmallIntervalInMills int
+	// LargeIntervalInMills is the read and write deadline of an idle connection, use
+	// DefaultLargeInterval if not positive
+	LargeIntervalInMills int
+}
+
+// durationInMills - convert the milliseconds to the duration, use the default if not positive
+func durationInMills(mills int, defaultValue time.Duration) time.Duration {
+	if mills <= 0 {
+		return defaultValue
+	}
+	return time.Duration(mills) * time.Millisecond
 }
 
 var customizeInit sync.Once
 
+// InitClient - initialize the http client and the transport shared by all the clients of the
+// process, only the config of the first call takes effect and the later calls are ignored, e.g.
+// the connection timeout, the keep-alive period and the read and write deadlines of the clients
+// created later.
+//
+// PARAMS:
+//   - config: the config of the shared http client
 func InitClient(config ClientConfig) {
 	customizeInit.Do(func() {
+		keepAlive := time.Duration(config.KeepAliveInMills) * time.Millisecond
+		if config.KeepAliveInMills == 0 {
+			keepAlive = DefaultKeepAlive
+		}
+		smallInterval := durationInMills(config.SmallIntervalInMills, DefaultSmallInterval)
+		largeInterval := durationInMills(config.LargeIntervalInMills, DefaultLargeInterval)
+		dialer := &net.Dialer{
+			Timeout:       time.Duration(config.ConnectionTimeoutInMills) * time.Millisecond,
+			KeepAlive:     keepAlive,
+			FallbackDelay: DefaultFallbackDelay,
+		}
 		httpClient = &http.Client{}
 		transport = &http.Transport{
 			MaxIdleConnsPerHost:   DefaultMaxIdleConnsPerHost,
 			ResponseHeaderTimeout: DefaultResponseHeaderTimeout,
-			Dial: func(network, address string) (net.Conn, error) {
-				conn, err := net.DialTimeout(network, address, time.Duration(config.ConnectionTimeoutInMills)*time.Millisecond)
+			// DialContext races the address families of the dual-stack endpoints(Happy Eyeballs)
+			// and stops dialing once the request is canceled
+			DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+				conn, err := dialer.DialContext(ctx, network, address)
 				if err != nil {
 					return nil, err
 				}
-				tc := &timeoutConn{conn, DefaultSmallInterval, DefaultLargeInterval}
-				err = tc.SetReadDeadline(time.Now().Add(DefaultLargeInterval))
+				tc := &timeoutConn{conn, smallInterval, largeInterval}
+				err = tc.SetReadDeadline(time.Now().Add(largeInterval))
 				if err != nil {
 					return nil, err
 				}
@@ -94,10 +138,8 @@ func InitClient(config ClientConfig) {
 			},
 		}
 		httpClient.Transport = transport
-		if config.RedirectDisabled {
-			httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
-				return http.ErrUseLastResponse
-			}
+		httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
 		}
 	})
 }
@@ -111,12 +153,12 @@ func InitClient(config ClientConfig) {
 //   - response: the http response returned from the server
 //   - error: nil if ok otherwise the specific error
 func Execute(request *Request) (*Response, error) {
-	// Build the request object for the current requesting
-	httpRequest := &http.Request{
+	// Build the request object for the current requesting, it is canceled with the context
+	httpRequest := (&http.Request{
 		Proto:      "HTTP/1.1",
 		ProtoMajor: 1,
 		ProtoMinor: 1,
-	}
+	}).WithContext(request.Context())
 
 	// Set the connection timeout for current request
 	httpClient.Timeout = time.Duration(request.Timeout()) * time.Second
@@ -167,7 +209,9 @@ func Execute(request *Request) (*Response, error) {
 
 	end := time.Now()
 	if err != nil {
-		transport.CloseIdleConnections()
+		if request.Context().Err() == nil {
+			transport.CloseIdleConnections()
+		}
 		return nil, err
 	}
 	if httpResponse.StatusCode >= 400 &&
@@ -177,3 +221,42 @@ func Execute(request *Request) (*Response, error) {
 	response := &Response{httpResponse, end.Sub(start)}
 	return response, nil
 }
+
+// Warmup - pre-establish at most n connections to the endpoint by sending n concurrent HEAD
+// requests, the connections are kept in the idle pool of the transport for the later requests. The
+// requests are not signed and their responses are ignored, only the connections count.
+//
+// PARAMS:
+//   - ctx: the context to cancel the warm-up
+//   - endpoint: the url of the endpoint, e.g. http://127.0.0.1:8511
+//   - n: the number of connections
+//
+// RETURNS:
+//   - error: the first error of the requests if any
+func Warmup(ctx context.Context, endpoint string, n int) error {
+	if httpClient == nil {
+		return errors.New("http client is not initialized")
+	}
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequestWithContext(ctx, Head, endpoint, nil)
+			if err == nil {
+				var resp *http.Response
+				if resp, err = httpClient.Do(req); err == nil {
+					_, _ = io.Copy(io.Discard, resp.Body)
+					resp.Body.Close()
+				}
+			}
+			if err != nil {
+				once.Do(func() { firstErr = err })
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
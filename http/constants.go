@@ -49,4 +49,6 @@ const (
 	// BCE Common HTTP Headers
 	RequestID        = "Request-ID"
 	RequestTimeoutMS = "Request-Timeout-MS"
+	BceDebugID       = "X-Bce-Debug-Id"
+	BceContentCRC32C = "X-Bce-Content-Crc32c"
 )
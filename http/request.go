@@ -17,6 +17,7 @@
 package http
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"strconv"
@@ -40,6 +41,9 @@ type Request struct {
 	// Optional body and length fields to set the body stream and content length
 	body   io.ReadCloser
 	length int64
+
+	// ctx cancels the request and bounds its deadline, context.Background() if nil
+	ctx context.Context
 }
 
 func (r *Request) Protocol() string {
@@ -106,8 +110,10 @@ func (r *Request) Headers() map[string]string {
 	return r.headers
 }
 
+// SetHeaders replaces the headers, the entries are copied so that the caller's map is not
+// modified when the request is reset for reuse.
 func (r *Request) SetHeaders(headers map[string]string) {
-	r.headers = headers
+	r.headers = copyToMap(r.headers, headers)
 }
 
 func (r *Request) Header(key string) string {
@@ -128,8 +134,10 @@ func (r *Request) Params() map[string]string {
 	return r.params
 }
 
+// SetParams replaces the params, the entries are copied so that the caller's map is not
+// modified when the request is reset for reuse.
 func (r *Request) SetParams(params map[string]string) {
-	r.params = params
+	r.params = copyToMap(r.params, params)
 }
 
 func (r *Request) Param(key string) string {
@@ -190,6 +198,19 @@ func (r *Request) SetTimeout(timeout int) {
 	r.timeout = timeout
 }
 
+// Context returns the context of the request, it is never nil
+func (r *Request) Context() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
+// SetContext sets the context to cancel the request or bound its deadline
+func (r *Request) SetContext(ctx context.Context) {
+	r.ctx = ctx
+}
+
 func (r *Request) Body() io.ReadCloser {
 	return r.body
 }
@@ -206,6 +227,31 @@ func (r *Request) SetLength(l int64) {
 	r.length = l
 }
 
+func copyToMap(dst, src map[string]string) map[string]string {
+	if dst == nil {
+		dst = make(map[string]string, len(src))
+	}
+	for k := range dst {
+		delete(dst, k)
+	}
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
+// Reset clears the request for reuse, the header and param maps are kept to avoid reallocation.
+func (r *Request) Reset() {
+	headers, params := r.headers, r.params
+	for k := range headers {
+		delete(headers, k)
+	}
+	for k := range params {
+		delete(params, k)
+	}
+	*r = Request{headers: headers, params: params}
+}
+
 func (r *Request) GenerateURL(addPort bool) string {
 	if addPort {
 		return fmt.Sprintf("%s://%s:%d%s?%s",
@@ -5,11 +5,14 @@
 package sdk
 
 import (
-	_ "github.com/baidu/mochow-sdk-go/auth"       // register auth package
-	_ "github.com/baidu/mochow-sdk-go/client"     // register client package
-	_ "github.com/baidu/mochow-sdk-go/http"       // register http package
-	_ "github.com/baidu/mochow-sdk-go/mochow"     // register mochow package
-	_ "github.com/baidu/mochow-sdk-go/mochow/api" // register api package
-	_ "github.com/baidu/mochow-sdk-go/util"       // register util package
-	_ "github.com/baidu/mochow-sdk-go/util/log"   // register log package
+	_ "github.com/baidu/mochow-sdk-go/auth"           // register auth package
+	_ "github.com/baidu/mochow-sdk-go/client"         // register client package
+	_ "github.com/baidu/mochow-sdk-go/http"           // register http package
+	_ "github.com/baidu/mochow-sdk-go/mochow"         // register mochow package
+	_ "github.com/baidu/mochow-sdk-go/mochow/api"     // register api package
+	_ "github.com/baidu/mochow-sdk-go/mochow/bulk"    // register bulk package
+	_ "github.com/baidu/mochow-sdk-go/mochow/codegen" // register codegen package
+	_ "github.com/baidu/mochow-sdk-go/mochow/filter"  // register filter package
+	_ "github.com/baidu/mochow-sdk-go/util"           // register util package
+	_ "github.com/baidu/mochow-sdk-go/util/log"       // register log package
 )
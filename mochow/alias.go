@@ -0,0 +1,251 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// alias.go - the resolution of the table aliases
+
+package mochow
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+const DefaultAliasCacheTTL = time.Minute
+
+// ErrAliasNotExist is returned by Resolve when the name is neither a table nor an alias
+var ErrAliasNotExist = errors.New("table or alias not exist")
+
+// AliasOptions defines the options of the alias resolution
+type AliasOptions struct {
+	// ResolveTables resolves the table names passed to the table, index and row methods of the
+	// Client, so that the application code can be written purely against the aliases
+	ResolveTables bool
+	// CacheTTL is the time the resolved names are cached, use DefaultAliasCacheTTL if zero and no
+	// caching if negative
+	CacheTTL time.Duration
+}
+
+type aliasEntry struct {
+	table    string
+	expireAt time.Time
+}
+
+type aliasResolver struct {
+	resolveTables bool
+	ttl           time.Duration
+
+	mu      sync.Mutex
+	entries map[string]aliasEntry // database and name -> table
+}
+
+func newAliasResolver(options *AliasOptions) *aliasResolver {
+	r := &aliasResolver{
+		resolveTables: options.ResolveTables,
+		ttl:           options.CacheTTL,
+		entries:       make(map[string]aliasEntry),
+	}
+	if r.ttl == 0 {
+		r.ttl = DefaultAliasCacheTTL
+	}
+	return r
+}
+
+func (r *aliasResolver) get(database, name string) (string, bool) {
+	if r == nil || r.ttl < 0 {
+		return "", false
+	}
+	key := cacheTableKey(database, name)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.entries[key]
+	if !ok {
+		return "", false
+	}
+	if time.Now().After(entry.expireAt) {
+		delete(r.entries, key)
+		return "", false
+	}
+	return entry.table, true
+}
+
+func (r *aliasResolver) put(database, name, table string) {
+	if r == nil || r.ttl < 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[cacheTableKey(database, name)] = aliasEntry{table: table, expireAt: time.Now().Add(r.ttl)}
+}
+
+func (r *aliasResolver) invalidate(database, name string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.entries, cacheTableKey(database, name))
+}
+
+// Resolve - resolve the alias to the name of the table it points at, the name of a table is
+// returned as is. The result is cached if the alias cache is enabled.
+//
+// PARAMS:
+//   - database: the database name
+//   - name: the alias or the table name
+//
+// RETURNS:
+//   - string: the table name
+//   - error: ErrAliasNotExist if not found, otherwise nil if ok or the specific error
+func (c *Client) Resolve(database, name string) (string, error) {
+	if table, ok := c.aliases.get(database, name); ok {
+		return table, nil
+	}
+	tables, err := api.ListTable(c, &api.ListTableArgs{Database: database})
+	if err != nil {
+		return "", err
+	}
+	for _, table := range tables.Tables {
+		if table == name {
+			c.aliases.put(database, name, table)
+			return table, nil
+		}
+	}
+	resolved := ""
+	for _, table := range tables.Tables {
+		desc, err := api.DescTable(c, &api.DescTableArgs{Database: database, Table: table})
+		if err != nil {
+			return "", err
+		}
+		if desc.Table == nil {
+			continue
+		}
+		for _, alias := range desc.Table.Aliases {
+			c.aliases.put(database, alias, table)
+			if alias == name {
+				resolved = table
+			}
+		}
+		if len(resolved) != 0 && c.aliases == nil {
+			break
+		}
+	}
+	if len(resolved) == 0 {
+		return "", ErrAliasNotExist
+	}
+	return resolved, nil
+}
+
+// resolveTable - resolve the table name if the table resolution is enabled
+func (c *Client) resolveTable(database, table string) (string, error) {
+	if c.aliases == nil || !c.aliases.resolveTables {
+		return table, nil
+	}
+	return c.Resolve(database, table)
+}
+
+// resolveArgs - resolve the table name of the args if the table resolution is enabled, args is the
+// pointer to the pointer of the args struct with the Database and Table fields, e.g. &args of
+// *api.SelectRowArgs, which is replaced by a resolved copy so that the args of the caller are kept
+func (c *Client) resolveArgs(args interface{}) error {
+	if c.aliases == nil || !c.aliases.resolveTables {
+		return nil
+	}
+	ptr := reflect.ValueOf(args).Elem()
+	if ptr.IsNil() {
+		return nil
+	}
+	fields := ptr.Elem()
+	table := fields.FieldByName("Table").String()
+	resolved, err := c.Resolve(fields.FieldByName("Database").String(), table)
+	if err != nil || resolved == table {
+		return err
+	}
+	clone := reflect.New(fields.Type())
+	clone.Elem().Set(fields)
+	clone.Elem().FieldByName("Table").SetString(resolved)
+	ptr.Set(clone)
+	return nil
+}
+
+// AliasChange points the alias at the table, an empty Table removes the alias
+type AliasChange struct {
+	Alias string
+	Table string
+}
+
+type aliasOp struct {
+	unalias bool
+	alias   string
+	table   string
+}
+
+func (c *Client) applyAliasOp(database string, op aliasOp) error {
+	if op.unalias {
+		return c.UnaliasTable(database, op.table, op.alias)
+	}
+	return c.AliasTable(database, op.table, op.alias)
+}
+
+// ChangeAliases - apply the alias changes as one unit, each alias is moved by unaliasing the
+// previous table and aliasing the new one. If any step fails, the applied steps are reverted in
+// the reverse order so that the aliases point at the previous tables.
+//
+// PARAMS:
+//   - database: the database name
+//   - changes: the alias changes
+//
+// RETURNS:
+//   - error: nil if ok otherwise the error of the failed step and the rollback if any
+func (c *Client) ChangeAliases(database string, changes []AliasChange) error {
+	var ops []aliasOp
+	for _, change := range changes {
+		c.aliases.invalidate(database, change.Alias)
+		current, err := c.Resolve(database, change.Alias)
+		if errors.Is(err, ErrAliasNotExist) {
+			current = ""
+		} else if err != nil {
+			return err
+		}
+		if current == change.Table {
+			continue
+		}
+		if len(current) != 0 {
+			ops = append(ops, aliasOp{unalias: true, alias: change.Alias, table: current})
+		}
+		if len(change.Table) != 0 {
+			ops = append(ops, aliasOp{alias: change.Alias, table: change.Table})
+		}
+	}
+
+	for i, op := range ops {
+		err := c.applyAliasOp(database, op)
+		if err == nil {
+			continue
+		}
+		for j := i - 1; j >= 0; j-- {
+			undo := ops[j]
+			undo.unalias = !undo.unalias
+			if rollbackErr := c.applyAliasOp(database, undo); rollbackErr != nil {
+				return fmt.Errorf("change aliases failed: %w, rollback failed: %v", err, rollbackErr)
+			}
+		}
+		return fmt.Errorf("change aliases failed and rolled back: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// binary.go - the base64 transport encoding of the BINARY fields
+
+package api
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// EncodeBinary - encode the bytes as the transported value of the BINARY field, the []byte values
+// of the rows are encoded by it when marshaled
+func EncodeBinary(data []byte) string {
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// DecodeBinary - decode the transported value of the BINARY field
+func DecodeBinary(value string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(value)
+}
+
+// GetBytes - get the BINARY field, the transported string is decoded
+func (d *Row) GetBytes(field string) ([]byte, error) {
+	value, err := d.get(field)
+	if err != nil {
+		return nil, err
+	}
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		data, err := DecodeBinary(v)
+		if err != nil {
+			return nil, fmt.Errorf("decode field %s failed: %w", field, err)
+		}
+		return data, nil
+	}
+	return nil, &FieldTypeError{Field: field, Value: value, Type: "[]byte"}
+}
+
+// DecodeBinaries - decode the string values of the BINARY and BINARY_VECTOR fields of the schema
+// into []byte in place
+//
+// PARAMS:
+//   - schema: the schema of the table
+//
+// RETURNS:
+//   - error: nil if ok otherwise the value can not be decoded
+func (d *Row) DecodeBinaries(schema *TableSchema) error {
+	for name, fieldType := range schemaFieldTypes(schema) {
+		if fieldType != FieldTypeBinary && fieldType != FieldTypeBinaryVector {
+			continue
+		}
+		s, ok := d.Fields[name].(string)
+		if !ok {
+			continue
+		}
+		data, err := DecodeBinary(s)
+		if err != nil {
+			return fmt.Errorf("decode field %s failed: %w", name, err)
+		}
+		d.Fields[name] = data
+	}
+	return nil
+}
+
+// DecodeFields - convert the transported values of the row to the go types by the schema, i.e.
+// the time fields to time.Time and the binary fields to []byte
+func (d *Row) DecodeFields(schema *TableSchema) error {
+	if err := d.DecodeTimes(schema); err != nil {
+		return err
+	}
+	return d.DecodeBinaries(schema)
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// cluster.go - the cluster APIs definition supported by the Mochow service
+
+package api
+
+import (
+	"github.com/baidu/mochow-sdk-go/client"
+	"github.com/baidu/mochow-sdk-go/http"
+)
+
+func DescCluster(cli client.Client) (*DescClusterResult, error) {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getClusterURI())
+	req.SetMethod(http.Post)
+	req.SetParam("desc", "")
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.IsFail() {
+		return nil, resp.ServiceError()
+	}
+	result := &DescClusterResult{}
+	if err := resp.ParseJSONBody(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func ListNodes(cli client.Client) (*ListNodesResult, error) {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getClusterURI())
+	req.SetMethod(http.Post)
+	req.SetParam("listNodes", "")
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.IsFail() {
+		return nil, resp.ServiceError()
+	}
+	result := &ListNodesResult{}
+	if err := resp.ParseJSONBody(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
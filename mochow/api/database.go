@@ -18,18 +18,17 @@
 package api
 
 import (
-	"github.com/bytedance/sonic"
-
 	"github.com/baidu/mochow-sdk-go/client"
 	"github.com/baidu/mochow-sdk-go/http"
 )
 
 func CreateDatabase(cli client.Client, args *CreateDatabaseArgs) error {
-	req := &client.BceRequest{}
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getDatabaseURI())
 	req.SetMethod(http.Post)
 	req.SetParam("create", "")
-	jsonBytes, err := sonic.Marshal(args)
+	jsonBytes, err := client.MarshalJSON(cli, args)
 	if err != nil {
 		return err
 	}
@@ -39,7 +38,8 @@ func CreateDatabase(cli client.Client, args *CreateDatabaseArgs) error {
 	}
 	req.SetBody(body)
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return err
 	}
@@ -51,12 +51,14 @@ func CreateDatabase(cli client.Client, args *CreateDatabaseArgs) error {
 }
 
 func DropDatabase(cli client.Client, database string) error {
-	req := &client.BceRequest{}
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getDatabaseURI())
 	req.SetMethod(http.Delete)
 	req.SetParam("database", database)
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return err
 	}
@@ -68,12 +70,14 @@ func DropDatabase(cli client.Client, database string) error {
 }
 
 func ListDatabase(cli client.Client) (*ListDatabaseResult, error) {
-	req := &client.BceRequest{}
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getDatabaseURI())
 	req.SetMethod(http.Post)
 	req.SetParam("list", "")
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return nil, err
 	}
@@ -86,3 +90,35 @@ func ListDatabase(cli client.Client) (*ListDatabaseResult, error) {
 	}
 	return result, nil
 }
+
+func DescDatabase(cli client.Client, args *DescDatabaseArgs) (*DescDatabaseResult, error) {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getDatabaseURI())
+	req.SetMethod(http.Post)
+	req.SetParam("desc", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return nil, err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.IsFail() {
+		return nil, resp.ServiceError()
+	}
+	result := &DescDatabaseResult{}
+	if err := resp.ParseJSONBody(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,299 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// encode.go - the append based json encoder for the hot entities of the row APIs
+
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/baidu/mochow-sdk-go/client"
+)
+
+const hexDigits = "0123456789abcdef"
+
+// appendJSONString - append the quoted and escaped string, the escaping follows encoding/json
+// except that the HTML characters are not escaped, which is the same as sonic.ConfigDefault.
+func appendJSONString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' {
+				i++
+				continue
+			}
+			buf = append(buf, s[start:i]...)
+			switch b {
+			case '"', '\\':
+				buf = append(buf, '\\', b)
+			case '\n':
+				buf = append(buf, '\\', 'n')
+			case '\r':
+				buf = append(buf, '\\', 'r')
+			case '\t':
+				buf = append(buf, '\\', 't')
+			default:
+				buf = append(buf, '\\', 'u', '0', '0', hexDigits[b>>4], hexDigits[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		c, size := utf8.DecodeRuneInString(s[i:])
+		if c == utf8.RuneError && size == 1 {
+			buf = append(buf, s[start:i]...)
+			buf = append(buf, `\ufffd`...)
+			i += size
+			start = i
+			continue
+		}
+		if c == '\u2028' || c == '\u2029' {
+			buf = append(buf, s[start:i]...)
+			buf = append(buf, '\\', 'u', '2', '0', '2', hexDigits[c&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	buf = append(buf, s[start:]...)
+	return append(buf, '"')
+}
+
+// appendJSONFloat - append the float in the same format as encoding/json
+func appendJSONFloat(buf []byte, f float64, bits int) ([]byte, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, fmt.Errorf("unsupported float value: %v", f)
+	}
+	abs := math.Abs(f)
+	format := byte('f')
+	if abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) ||
+			bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	buf = strconv.AppendFloat(buf, f, format, -1, bits)
+	if format == 'e' {
+		// clean up e-09 to e-9
+		n := len(buf)
+		if n >= 4 && buf[n-4] == 'e' && buf[n-3] == '-' && buf[n-2] == '0' {
+			buf[n-2] = buf[n-1]
+			buf = buf[:n-1]
+		}
+	}
+	return buf, nil
+}
+
+// appendJSONValue - append the value of the row field, the common scalar and vector types are
+// encoded directly and the others fall back to sonic.
+func appendJSONValue(buf []byte, value interface{}) ([]byte, error) {
+	var err error
+	switch v := value.(type) {
+	case nil:
+		buf = append(buf, "null"...)
+	case string:
+		buf = appendJSONString(buf, v)
+	case bool:
+		buf = strconv.AppendBool(buf, v)
+	case int:
+		buf = strconv.AppendInt(buf, int64(v), 10)
+	case int8:
+		buf = strconv.AppendInt(buf, int64(v), 10)
+	case int16:
+		buf = strconv.AppendInt(buf, int64(v), 10)
+	case int32:
+		buf = strconv.AppendInt(buf, int64(v), 10)
+	case int64:
+		buf = strconv.AppendInt(buf, v, 10)
+	case uint:
+		buf = strconv.AppendUint(buf, uint64(v), 10)
+	case uint8:
+		buf = strconv.AppendUint(buf, uint64(v), 10)
+	case uint16:
+		buf = strconv.AppendUint(buf, uint64(v), 10)
+	case uint32:
+		buf = strconv.AppendUint(buf, uint64(v), 10)
+	case uint64:
+		buf = strconv.AppendUint(buf, v, 10)
+	case float32:
+		buf, err = appendJSONFloat(buf, float64(v), 32)
+	case float64:
+		buf, err = appendJSONFloat(buf, v, 64)
+	case json.Number:
+		if len(v) == 0 {
+			buf = append(buf, '0')
+		} else {
+			buf = append(buf, v...)
+		}
+	case []byte:
+		// the BINARY and BINARY_VECTOR values are transported in standard base64
+		if v == nil {
+			return append(buf, "null"...), nil
+		}
+		n := len(buf) + 1
+		buf = append(buf, make([]byte, base64.StdEncoding.EncodedLen(len(v))+2)...)
+		base64.StdEncoding.Encode(buf[n:], v)
+		buf[n-1], buf[len(buf)-1] = '"', '"'
+	case []float32:
+		if v == nil {
+			return append(buf, "null"...), nil
+		}
+		buf = append(buf, '[')
+		for i, f := range v {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			if buf, err = appendJSONFloat(buf, float64(f), 32); err != nil {
+				return nil, err
+			}
+		}
+		buf = append(buf, ']')
+	case []float64:
+		if v == nil {
+			return append(buf, "null"...), nil
+		}
+		buf = append(buf, '[')
+		for i, f := range v {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			if buf, err = appendJSONFloat(buf, f, 64); err != nil {
+				return nil, err
+			}
+		}
+		buf = append(buf, ']')
+	default:
+		var data []byte
+		if data, err = sonic.Marshal(v); err == nil {
+			buf = append(buf, data...)
+		}
+	}
+	return buf, err
+}
+
+// appendJSONFields - append the fields of the row as a json object in the map order
+func appendJSONFields(buf []byte, fields map[string]interface{}) ([]byte, error) {
+	if fields == nil {
+		return append(buf, "null"...), nil
+	}
+	var err error
+	buf = append(buf, '{')
+	first := true
+	for key, value := range fields {
+		if !first {
+			buf = append(buf, ',')
+		}
+		first = false
+		buf = appendJSONString(buf, key)
+		buf = append(buf, ':')
+		if buf, err = appendJSONValue(buf, value); err != nil {
+			return nil, fmt.Errorf("marshal field %s failed: %w", key, err)
+		}
+	}
+	return append(buf, '}'), nil
+}
+
+// defaultJSONEncoding reports whether the client marshals with the default options, the rows and
+// the field schemas are then appended directly by their json.Marshaler. Otherwise they are
+// marshaled by the codec configured by the client as plain maps, since the json.Marshaler does
+// not know the options, e.g. SortMapKeys and EscapeHTML. The marshaling options added to
+// client.JSONOptions should be checked here as well.
+func defaultJSONEncoding(cli client.Client) bool {
+	if cli == nil {
+		return true
+	}
+	conf := cli.GetBceClientConfig()
+	if conf == nil || conf.JSONOptions == nil {
+		return true
+	}
+	return !conf.JSONOptions.SortMapKeys && !conf.JSONOptions.EscapeHTML
+}
+
+// rowFields - get the field maps of the rows to be marshaled by the configured codec
+func rowFields(rows []Row) []map[string]interface{} {
+	if rows == nil {
+		return nil
+	}
+	fields := make([]map[string]interface{}, len(rows))
+	for i := range rows {
+		fields[i] = rows[i].Fields
+	}
+	return fields
+}
+
+// codecTableSchema is the TableSchema whose fields are marshaled by the configured codec
+type codecTableSchema struct {
+	Fields  []map[string]interface{} `json:"fields,omitempty"`
+	Indexes []IndexSchema            `json:"indexes,omitempty"`
+}
+
+func newCodecTableSchema(schema *TableSchema) *codecTableSchema {
+	if schema == nil {
+		return nil
+	}
+	codecSchema := &codecTableSchema{Indexes: schema.Indexes}
+	if schema.Fields != nil {
+		codecSchema.Fields = make([]map[string]interface{}, len(schema.Fields))
+		for i := range schema.Fields {
+			codecSchema.Fields[i] = schema.Fields[i].fieldMap()
+		}
+	}
+	return codecSchema
+}
+
+// marshalRowArgs - marshal the args of the row writes with the json codec configured by the
+// client, see defaultJSONEncoding
+func marshalRowArgs(cli client.Client, args *InsertRowArgs) ([]byte, error) {
+	if defaultJSONEncoding(cli) {
+		return client.MarshalJSON(cli, args)
+	}
+	return client.MarshalJSON(cli, &struct {
+		*InsertRowArgs
+		Rows []map[string]interface{} `json:"rows,omitempty"`
+	}{args, rowFields(args.Rows)})
+}
+
+// marshalCreateTableArgs - marshal the args of CreateTable with the json codec configured by the
+// client, see defaultJSONEncoding
+func marshalCreateTableArgs(cli client.Client, args *CreateTableArgs) ([]byte, error) {
+	if defaultJSONEncoding(cli) {
+		return client.MarshalJSON(cli, args)
+	}
+	return client.MarshalJSON(cli, &struct {
+		*CreateTableArgs
+		Schema *codecTableSchema `json:"schema,omitempty"`
+	}{args, newCodecTableSchema(args.Schema)})
+}
+
+// marshalAddFieldArgs - marshal the args of AddField with the json codec configured by the
+// client, see defaultJSONEncoding
+func marshalAddFieldArgs(cli client.Client, args *AddFieldArgs) ([]byte, error) {
+	if defaultJSONEncoding(cli) {
+		return client.MarshalJSON(cli, args)
+	}
+	return client.MarshalJSON(cli, &struct {
+		*AddFieldArgs
+		Schema *codecTableSchema `json:"schema,omitempty"`
+	}{args, newCodecTableSchema(args.Schema)})
+}
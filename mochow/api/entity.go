@@ -18,6 +18,11 @@ package api
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
 
 	"github.com/bytedance/sonic"
 	"github.com/bytedance/sonic/decoder"
@@ -26,6 +31,10 @@ import (
 type PartitionParams struct {
 	PartitionType PartitionType `json:"partitionType,omitempty"`
 	PartitionNum  uint32        `json:"partitionNum"`
+	// Ranges are the partitions of the RANGE partition in the ascending order of the bounds
+	Ranges []RangePartition `json:"ranges,omitempty"`
+	// Lists are the partitions of the LIST partition
+	Lists []ListPartition `json:"lists,omitempty"`
 }
 
 type FieldSchema struct {
@@ -39,7 +48,43 @@ type FieldSchema struct {
 }
 
 func (f *FieldSchema) MarshalJSON() ([]byte, error) {
-	fields := make(map[string]interface{})
+	buf := make([]byte, 0, 128)
+	buf = append(buf, '{')
+	if len(f.FieldName) > 0 {
+		buf = append(buf, `"fieldName":`...)
+		buf = appendJSONString(buf, f.FieldName)
+		buf = append(buf, ',')
+	}
+	if len(f.FieldType) > 0 {
+		buf = append(buf, `"fieldType":`...)
+		buf = appendJSONString(buf, string(f.FieldType))
+		buf = append(buf, ',')
+	}
+	if f.Dimension > 0 {
+		buf = append(buf, `"dimension":`...)
+		buf = strconv.AppendUint(buf, uint64(f.Dimension), 10)
+		buf = append(buf, ',')
+	}
+	buf = append(buf, `"primaryKey":`...)
+	buf = strconv.AppendBool(buf, f.PrimaryKey)
+	buf = append(buf, `,"partitionKey":`...)
+	buf = strconv.AppendBool(buf, f.PartitionKey)
+	buf = append(buf, `,"autoIncrement":`...)
+	buf = strconv.AppendBool(buf, f.AutoIncrement)
+	buf = append(buf, `,"notNull":`...)
+	buf = strconv.AppendBool(buf, f.NotNull)
+	buf = append(buf, '}')
+	return buf, nil
+}
+
+// fieldMap - get the fields of the schema in the same form as MarshalJSON
+func (f *FieldSchema) fieldMap() map[string]interface{} {
+	fields := map[string]interface{}{
+		"primaryKey":    f.PrimaryKey,
+		"partitionKey":  f.PartitionKey,
+		"autoIncrement": f.AutoIncrement,
+		"notNull":       f.NotNull,
+	}
 	if len(f.FieldName) > 0 {
 		fields["fieldName"] = f.FieldName
 	}
@@ -49,32 +94,68 @@ func (f *FieldSchema) MarshalJSON() ([]byte, error) {
 	if f.Dimension > 0 {
 		fields["dimension"] = f.Dimension
 	}
-	fields["primaryKey"] = f.PrimaryKey
-	fields["partitionKey"] = f.PartitionKey
-	fields["autoIncrement"] = f.AutoIncrement
-	fields["notNull"] = f.NotNull
-	field, err := sonic.Marshal(fields)
-	if err != nil {
-		return nil, err
-	}
-	return field, nil
+	return fields
 }
 
 type VectorIndexParams map[string]interface{}
 
 type AutoBuildParams map[string]interface{}
 
+// IndexSchema defines the index, Fields are the fields of the composite SECONDARY index in the
+// order of the key and either Field or Fields is set
 type IndexSchema struct {
 	IndexName       string            `json:"indexName,omitempty"`
 	IndexType       IndexType         `json:"indexType,omitempty"`
 	MetricType      MetricType        `json:"metricType,omitempty"`
 	Params          VectorIndexParams `json:"params,omitempty"`
 	Field           string            `json:"field,omitempty"`
+	Fields          []string          `json:"fields,omitempty"`
 	State           IndexState        `json:"state,omitempty"`
 	AutoBuild       bool              `json:"autoBuild,omitempty"`
 	AutoBuildPolicy AutoBuildParams   `json:"autoBuildPolicy,omitempty"`
 }
 
+// UnmarshalJSON - decode the index, the composite index described with an array of the fields
+// as its field is decoded into Fields
+func (s *IndexSchema) UnmarshalJSON(data []byte) error {
+	type indexSchema IndexSchema
+	aux := struct {
+		*indexSchema
+		Field interface{} `json:"field,omitempty"`
+	}{indexSchema: (*indexSchema)(s)}
+	if err := sonic.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	switch field := aux.Field.(type) {
+	case nil:
+	case string:
+		s.Field = field
+	case []interface{}:
+		s.Fields = make([]string, len(field))
+		for i := range field {
+			name, ok := field[i].(string)
+			if !ok {
+				return fmt.Errorf("invalid field %v of index %s", field[i], s.IndexName)
+			}
+			s.Fields[i] = name
+		}
+	default:
+		return fmt.Errorf("invalid field %v of index %s", field, s.IndexName)
+	}
+	return nil
+}
+
+// IndexedFields returns the fields of the index, i.e. Fields of the composite index or Field
+func (s *IndexSchema) IndexedFields() []string {
+	if len(s.Fields) != 0 {
+		return s.Fields
+	}
+	if len(s.Field) != 0 {
+		return []string{s.Field}
+	}
+	return nil
+}
+
 type TableSchema struct {
 	Fields  []FieldSchema `json:"fields,omitempty"`
 	Indexes []IndexSchema `json:"indexes,omitempty"`
@@ -91,18 +172,19 @@ type TableDescription struct {
 	State              TableState       `json:"state"`
 	Aliases            []string         `json:"aliases,omitempty"`
 	Schema             *TableSchema     `json:"schema,omitempty"`
+	TTLField           string           `json:"ttlField,omitempty"`
+	TTLSeconds         uint64           `json:"ttlSeconds,omitempty"`
 }
 
 type Row struct {
 	Fields map[string]interface{} `json:"-"`
+	// TTLSeconds expires the row TTLSeconds after the write, it overrides the TTLSeconds of the
+	// write if positive, the rows of different TTLs are written by separate requests
+	TTLSeconds uint64 `json:"-"`
 }
 
 func (d *Row) MarshalJSON() ([]byte, error) {
-	field, err := sonic.Marshal(d.Fields)
-	if err != nil {
-		return nil, err
-	}
-	return field, nil
+	return appendJSONFields(make([]byte, 0, 32*len(d.Fields)+2), d.Fields)
 }
 
 func (d *Row) UnmarshalJSON(data []byte) error {
@@ -115,8 +197,13 @@ func (d *Row) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// SearchParams defines the params of the ann search. It is safe to share one SearchParams across
+// goroutines, e.g. a fixed ef and limit, as long as it is modified via the Add methods, accessing
+// the Params map directly is not synchronized.
 type SearchParams struct {
 	Params map[string]interface{}
+
+	mu sync.RWMutex
 }
 
 func NewSearchParams() *SearchParams {
@@ -125,39 +212,89 @@ func NewSearchParams() *SearchParams {
 	}
 }
 
+func (h *SearchParams) set(key string, value interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.Params == nil {
+		h.Params = make(map[string]interface{})
+	}
+	h.Params[key] = value
+}
+
+func (h *SearchParams) has(key string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	_, ok := h.Params[key]
+	return ok
+}
+
 func (h *SearchParams) AddEf(ef uint32) {
-	h.Params["ef"] = ef
+	h.set("ef", ef)
 }
 
 func (h *SearchParams) AddDistanceNear(distanceNear float64) {
-	h.Params["distanceNear"] = distanceNear
+	h.set("distanceNear", distanceNear)
 }
 
 func (h *SearchParams) AddDistanceFar(distanceFar float64) {
-	h.Params["distanceFar"] = distanceFar
+	h.set("distanceFar", distanceFar)
 }
 
 func (h *SearchParams) AddLimit(limit uint32) {
-	h.Params["limit"] = limit
+	h.set("limit", limit)
 }
 
 func (h *SearchParams) AddPruning(pruning bool) {
-	h.Params["pruning"] = pruning
+	h.set("pruning", pruning)
 }
 
 func (h *SearchParams) AddSearchCoarseCount(searchCoarseCount uint32) {
-	h.Params["searchCoarseCount"] = searchCoarseCount
+	h.set("searchCoarseCount", searchCoarseCount)
+}
+
+// Clone returns a copy of the params which can be modified independently
+func (h *SearchParams) Clone() *SearchParams {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	params := make(map[string]interface{}, len(h.Params))
+	for k, v := range h.Params {
+		params[k] = v
+	}
+	return &SearchParams{Params: params}
 }
 
 func (h *SearchParams) MarshalJSON() ([]byte, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
 	return sonic.Marshal(h.Params)
 }
 
 type ANNSearchParams struct {
-	VectorField  string        `json:"vectorField,omitempty"`
-	VectorFloats []float32     `json:"vectorFloats,omitempty"`
+	VectorField  string    `json:"vectorField,omitempty"`
+	VectorFloats []float32 `json:"vectorFloats,omitempty"`
+	// VectorBinary is the bit-packed query vector of the BINARY_VECTOR field instead of
+	// VectorFloats, see util.PackBits
+	VectorBinary []byte        `json:"vectorBinary,omitempty"`
 	Params       *SearchParams `json:"params,omitempty'"`
 	Filter       string        `json:"filter,omitempty"`
+	// TopK is the number of the returned rows and Offset skips the nearer rows, e.g. the third
+	// page of 10 rows is TopK 10 and Offset 20. TopK replaces the limit of Params.
+	TopK   uint32 `json:"topK,omitempty"`
+	Offset uint32 `json:"offset,omitempty"`
+}
+
+// Validate - check that Offset is set with TopK and TopK is not set with the limit of the params
+func (a *ANNSearchParams) Validate() error {
+	if a.Offset != 0 && a.TopK == 0 {
+		return errors.New("topK should be set with offset")
+	}
+	if a.TopK != 0 && a.Params != nil && a.Params.has("limit") {
+		return errors.New("limit of the params should not be set with topK")
+	}
+	if uint64(a.Offset)+uint64(a.TopK) > math.MaxUint32 {
+		return fmt.Errorf("offset %d plus topK %d overflows", a.Offset, a.TopK)
+	}
+	return nil
 }
 
 type BatchANNSearchParams struct {
@@ -167,6 +304,62 @@ type BatchANNSearchParams struct {
 	Filter       string        `json:"filter,omitempty"`
 }
 
+// BM25SearchParams defines the keyword search over the inverted index of the TEXT fields, the
+// rows are ranked by the BM25 relevance
+type BM25SearchParams struct {
+	IndexName  string `json:"indexName"`
+	SearchText string `json:"searchText"`
+}
+
+// FusionParams defines how the vector and keyword results are merged, the RRF fusion is used if
+// FusionType is empty
+type FusionParams struct {
+	FusionType FusionType `json:"fusionType,omitempty"`
+	// K is the rank constant of the RRF fusion, the server default is used if zero
+	K uint32 `json:"k,omitempty"`
+	// VectorWeight and BM25Weight are the weights of the WEIGHTED_SUM fusion
+	VectorWeight float64 `json:"vectorWeight,omitempty"`
+	BM25Weight   float64 `json:"bm25Weight,omitempty"`
+}
+
+// HybridSearchParams combines the ann search and the BM25 keyword search, the Filter applies to
+// both searches and Limit caps the number of the fused rows
+type HybridSearchParams struct {
+	ANNS   *ANNSearchParams  `json:"anns"`
+	BM25   *BM25SearchParams `json:"bm25"`
+	Fusion *FusionParams     `json:"fusion,omitempty"`
+	Filter string            `json:"filter,omitempty"`
+	Limit  uint32            `json:"limit,omitempty"`
+}
+
+// Validate - check that both searches are set and the fusion params are consistent
+func (h *HybridSearchParams) Validate() error {
+	if h.ANNS == nil || h.BM25 == nil {
+		return errors.New("both anns and bm25 searches should be set")
+	}
+	if len(h.BM25.IndexName) == 0 || len(h.BM25.SearchText) == 0 {
+		return errors.New("indexName and searchText of bm25 search should not be empty")
+	}
+	if h.Fusion == nil {
+		return nil
+	}
+	switch h.Fusion.FusionType {
+	case "", FusionRRF:
+		if h.Fusion.VectorWeight != 0 || h.Fusion.BM25Weight != 0 {
+			return errors.New("weights are only supported by the WEIGHTED_SUM fusion")
+		}
+	case FusionWeightedSum:
+		if h.Fusion.VectorWeight < 0 || h.Fusion.BM25Weight < 0 ||
+			h.Fusion.VectorWeight+h.Fusion.BM25Weight == 0 {
+			return fmt.Errorf("weights %v and %v should be non-negative and not both zero",
+				h.Fusion.VectorWeight, h.Fusion.BM25Weight)
+		}
+	default:
+		return fmt.Errorf("unknown fusion type %s", h.Fusion.FusionType)
+	}
+	return nil
+}
+
 type AutoBuildPolicy interface {
 	Params() map[string]interface{}
 	AddTiming(timing string)
@@ -22,6 +22,10 @@ const (
 	L2     MetricType = "L2"
 	IP     MetricType = "IP"
 	COSINE MetricType = "COSINE"
+
+	// binary vector metric type
+	HAMMING MetricType = "HAMMING"
+	JACCARD MetricType = "JACCARD"
 )
 
 type IndexType string
@@ -33,8 +37,38 @@ const (
 	PUCK   IndexType = "PUCK"
 	HNSWPQ IndexType = "HNSWPQ"
 
+	// binary vector index type
+	BinaryFlat IndexType = "BINARY_FLAT"
+	BinaryHNSW IndexType = "BINARY_HNSW"
+
 	// scalar index type
 	SecondaryIndex IndexType = "SECONDARY"
+
+	// InvertedIndex is the full-text index of the TEXT, TEXT_GBK and TEXT_GB18030 fields, it is
+	// used by the BM25 searches
+	InvertedIndex IndexType = "INVERTED"
+)
+
+// InvertedIndexAnalyzer is the analyzer to split the text into the terms of the inverted index
+type InvertedIndexAnalyzer string
+
+const (
+	EnglishAnalyzer InvertedIndexAnalyzer = "ENGLISH_ANALYZER"
+	// ChineseAnalyzer segments the chinese words, it is required by the TEXT_GBK and TEXT_GB18030
+	// fields
+	ChineseAnalyzer InvertedIndexAnalyzer = "CHINESE_ANALYZER"
+	// DefaultAnalyzer segments both the chinese and english words
+	DefaultAnalyzer InvertedIndexAnalyzer = "DEFAULT_ANALYZER"
+)
+
+// InvertedIndexParseMode is the granularity of the chinese word segmentation
+type InvertedIndexParseMode string
+
+const (
+	// CoarseMode splits the text into the longest words
+	CoarseMode InvertedIndexParseMode = "COARSE_MODE"
+	// FineMode also indexes the shorter words in the long words, e.g. more recall and bigger index
+	FineMode InvertedIndexParseMode = "FINE_MODE"
 )
 
 type FieldType string
@@ -61,9 +95,13 @@ const (
 	FieldTypeText        FieldType = "TEXT"
 	FieldTypeTextGBK     FieldType = "TEXT_GBK"
 	FieldTypeTextGB18030 FieldType = "TEXT_GB18030"
+	// FieldTypeJSON is the json object, its nested values are filtered by the paths, e.g.
+	// "attrs.color = 'red'"
+	FieldTypeJSON FieldType = "JSON"
 
 	// vector field type
-	FieldTypeFloatVector FieldType = "FLOAT_VECTOR"
+	FieldTypeFloatVector  FieldType = "FLOAT_VECTOR"
+	FieldTypeBinaryVector FieldType = "BINARY_VECTOR"
 )
 
 type AutoBuildPolicyType string
@@ -78,13 +116,67 @@ type PartitionType string
 
 const (
 	HASH PartitionType = "HASH"
+	// RANGE partitions the rows by the ranges of the partition key, e.g. the months of the time
+	RANGE PartitionType = "RANGE"
+	// LIST partitions the rows by the lists of the values of the partition key
+	LIST PartitionType = "LIST"
 )
 
+// ReadConsistency is the consistency of the read requests, the server uses EVENTUAL if empty
 type ReadConsistency string
 
 const (
+	// EVENTUAL reads from any replica, the recent writes may not be visible
 	EVENTUAL ReadConsistency = "EVENTUAL"
-	STRONG   ReadConsistency = "STRONG"
+	// STRONG reads the latest writes from the leader replicas
+	STRONG ReadConsistency = "STRONG"
+)
+
+type SortOrder string
+
+const (
+	ASC  SortOrder = "ASC"
+	DESC SortOrder = "DESC"
+)
+
+type AggregateFunc string
+
+const (
+	AggregateCount AggregateFunc = "COUNT"
+	AggregateMin   AggregateFunc = "MIN"
+	AggregateMax   AggregateFunc = "MAX"
+	AggregateSum   AggregateFunc = "SUM"
+)
+
+// FusionType is the strategy to merge the results of the vector and keyword searches
+type FusionType string
+
+const (
+	// FusionRRF ranks the rows by the sum of 1/(k+rank) of the searches
+	FusionRRF FusionType = "RRF"
+	// FusionWeightedSum ranks the rows by the weighted sum of the normalized scores
+	FusionWeightedSum FusionType = "WEIGHTED_SUM"
+)
+
+// Privilege is the permission of the users and roles on the databases and tables
+type Privilege string
+
+const (
+	PrivilegeAll            Privilege = "ALL"
+	PrivilegeUsage          Privilege = "USAGE"
+	PrivilegeCreateDatabase Privilege = "CREATE_DATABASE"
+	PrivilegeDropDatabase   Privilege = "DROP_DATABASE"
+	PrivilegeCreateTable    Privilege = "CREATE_TABLE"
+	PrivilegeDropTable      Privilege = "DROP_TABLE"
+	PrivilegeAlterTable     Privilege = "ALTER_TABLE"
+	PrivilegeCreateIndex    Privilege = "CREATE_INDEX"
+	PrivilegeDropIndex      Privilege = "DROP_INDEX"
+	PrivilegeInsert         Privilege = "INSERT"
+	PrivilegeUpdate         Privilege = "UPDATE"
+	PrivilegeDelete         Privilege = "DELETE"
+	PrivilegeQuery          Privilege = "QUERY"
+	PrivilegeSelect         Privilege = "SELECT"
+	PrivilegeSearch         Privilege = "SEARCH"
 )
 
 type TableState string
@@ -102,6 +194,45 @@ const (
 	IndexStateNormal   IndexState = "NORMAL"
 )
 
+// TaskType is the kind of the long running operation executed by the background task
+type TaskType string
+
+const (
+	TaskTypeRebuildIndex TaskType = "REBUILD_INDEX"
+	TaskTypeBackup       TaskType = "BACKUP"
+	TaskTypeCompaction   TaskType = "COMPACTION"
+	TaskTypeFlush        TaskType = "FLUSH"
+	TaskTypeRebalance    TaskType = "REBALANCE_PARTITION"
+	TaskTypeResplit      TaskType = "RESPLIT_PARTITION"
+)
+
+type TaskState string
+
+const (
+	TaskStatePending   TaskState = "PENDING"
+	TaskStateRunning   TaskState = "RUNNING"
+	TaskStateSucceeded TaskState = "SUCCEEDED"
+	TaskStateFailed    TaskState = "FAILED"
+	TaskStateCanceled  TaskState = "CANCELED"
+)
+
+// NodeRole is the role of the node in the cluster
+type NodeRole string
+
+const (
+	NodeRoleMaster   NodeRole = "MASTER"
+	NodeRoleProxy    NodeRole = "PROXY"
+	NodeRoleDatanode NodeRole = "DATANODE"
+)
+
+type NodeState string
+
+const (
+	NodeStateHealthy   NodeState = "HEALTHY"
+	NodeStateUnhealthy NodeState = "UNHEALTHY"
+	NodeStateOffline   NodeState = "OFFLINE"
+)
+
 type ServerErrCode int32
 
 const (
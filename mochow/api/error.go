@@ -0,0 +1,160 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// error.go - the predicates to check the server error code of the Mochow service
+
+package api
+
+import (
+	"errors"
+
+	"github.com/baidu/mochow-sdk-go/client"
+)
+
+// ErrorCode returns the server error code carried by the error, the second return value is false
+// if the error is not returned by the Mochow service.
+func ErrorCode(err error) (ServerErrCode, bool) {
+	var serviceErr *client.BceServiceError
+	if errors.As(err, &serviceErr) {
+		return ServerErrCode(serviceErr.Code), true
+	}
+	return OK, false
+}
+
+// Is reports whether the error is a service error with the code, it covers every ServerErrCode,
+// e.g. `api.DBNotEmpty.Is(err)`.
+func (c ServerErrCode) Is(err error) bool {
+	code, ok := ErrorCode(err)
+	return ok && code == c
+}
+
+// IsNotExist reports whether the error means that the target user, role, database, table, alias,
+// field or index does not exist.
+func IsNotExist(err error) bool {
+	code, ok := ErrorCode(err)
+	if !ok {
+		return false
+	}
+	switch code {
+	case UserNotExist, RoleNotExist, DBNotExist, TableNotExist, AliasNotExist,
+		FieldNotExist, VectorFieldNotExist, IndexNotExist:
+		return true
+	}
+	return false
+}
+
+// IsAlreadyExist reports whether the error means that the target user, role, database, table,
+// alias, field, index or primary key already exists.
+func IsAlreadyExist(err error) bool {
+	code, ok := ErrorCode(err)
+	if !ok {
+		return false
+	}
+	switch code {
+	case UserAlreadyExist, RoleAlreadyExist, DBAlreadyExist, TableAlreadyExist, AliasAlreadyExist,
+		FieldAlreadyExist, IndexAlreadyExist, IndexDuplicated, PrimaryKeyDuplicated:
+		return true
+	}
+	return false
+}
+
+// IsInternalError reports whether the error is an internal error of the Mochow service.
+func IsInternalError(err error) bool { return InternalError.Is(err) }
+
+// IsInvalidParameter reports whether the error is caused by invalid request parameters.
+func IsInvalidParameter(err error) bool {
+	code, ok := ErrorCode(err)
+	if !ok {
+		return false
+	}
+	switch code {
+	case InvalidParameter, InvalidHTTPURL, InvalidHTTPHeader, InvalidHTTPBody,
+		InvalidTableSchema, InvalidPartitionParameters, InvalidIndexSchema:
+		return true
+	}
+	return false
+}
+
+// IsInvalidState reports whether the error is caused by the state of the table or index.
+func IsInvalidState(err error) bool {
+	code, ok := ErrorCode(err)
+	if !ok {
+		return false
+	}
+	switch code {
+	case InvalidTableState, TableNotReady, InvalidIndexState:
+		return true
+	}
+	return false
+}
+
+// IsTooMany reports whether the error is caused by exceeding the limits of the database or table.
+func IsTooMany(err error) bool {
+	code, ok := ErrorCode(err)
+	if !ok {
+		return false
+	}
+	switch code {
+	case DBTooManyTables, TableTooManyFields, TableTooManyFamilies, TableTooManyPrimaryKeys,
+		TableTooManyPartitionKeys, TableTooManyVectorFields, TableTooManyIndexes:
+		return true
+	}
+	return false
+}
+
+func IsMissSSLCertificates(err error) bool { return MissSSLCertificates.Is(err) }
+
+func IsUserNotExist(err error) bool { return UserNotExist.Is(err) }
+
+func IsUserAlreadyExist(err error) bool { return UserAlreadyExist.Is(err) }
+
+func IsRoleNotExist(err error) bool { return RoleNotExist.Is(err) }
+
+func IsRoleAlreadyExist(err error) bool { return RoleAlreadyExist.Is(err) }
+
+func IsAuthenticationFailed(err error) bool { return AuthenticationFailed.Is(err) }
+
+func IsPermissionDenied(err error) bool { return PermissionDenied.Is(err) }
+
+func IsDatabaseNotExist(err error) bool { return DBNotExist.Is(err) }
+
+func IsDatabaseAlreadyExist(err error) bool { return DBAlreadyExist.Is(err) }
+
+func IsDatabaseNotEmpty(err error) bool { return DBNotEmpty.Is(err) }
+
+func IsDynamicSchemaError(err error) bool { return DynamicSchemaError.Is(err) }
+
+func IsTableNotExist(err error) bool { return TableNotExist.Is(err) }
+
+func IsTableAlreadyExist(err error) bool { return TableAlreadyExist.Is(err) }
+
+func IsTableNotReady(err error) bool { return TableNotReady.Is(err) }
+
+func IsAliasNotExist(err error) bool { return AliasNotExist.Is(err) }
+
+func IsAliasAlreadyExist(err error) bool { return AliasAlreadyExist.Is(err) }
+
+func IsFieldNotExist(err error) bool { return FieldNotExist.Is(err) }
+
+func IsFieldAlreadyExist(err error) bool { return FieldAlreadyExist.Is(err) }
+
+func IsVectorFieldNotExist(err error) bool { return VectorFieldNotExist.Is(err) }
+
+func IsIndexNotExist(err error) bool { return IndexNotExist.Is(err) }
+
+func IsIndexAlreadyExist(err error) bool { return IndexAlreadyExist.Is(err) }
+
+func IsIndexDuplicated(err error) bool { return IndexDuplicated.Is(err) }
+
+func IsPrimaryKeyDuplicated(err error) bool { return PrimaryKeyDuplicated.Is(err) }
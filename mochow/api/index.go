@@ -17,19 +17,18 @@
 package api
 
 import (
-	"github.com/bytedance/sonic"
-
 	"github.com/baidu/mochow-sdk-go/client"
 	"github.com/baidu/mochow-sdk-go/http"
 )
 
 func CreateIndex(cli client.Client, args *CreateIndexArgs) error {
-	req := &client.BceRequest{}
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getIndexURI())
 	req.SetMethod(http.Post)
 	req.SetParam("create", "")
 
-	jsonBytes, err := sonic.Marshal(args)
+	jsonBytes, err := client.MarshalJSON(cli, args)
 	if err != nil {
 		return err
 	}
@@ -39,7 +38,8 @@ func CreateIndex(cli client.Client, args *CreateIndexArgs) error {
 	}
 	req.SetBody(body)
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return err
 	}
@@ -51,12 +51,13 @@ func CreateIndex(cli client.Client, args *CreateIndexArgs) error {
 }
 
 func DescIndex(cli client.Client, args *DescIndexArgs) (*DescIndexResult, error) {
-	req := &client.BceRequest{}
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getIndexURI())
 	req.SetMethod(http.Post)
 	req.SetParam("desc", "")
 
-	jsonBytes, err := sonic.Marshal(args)
+	jsonBytes, err := client.MarshalJSON(cli, args)
 	if err != nil {
 		return nil, err
 	}
@@ -66,7 +67,8 @@ func DescIndex(cli client.Client, args *DescIndexArgs) (*DescIndexResult, error)
 	}
 	req.SetBody(body)
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return nil, err
 	}
@@ -80,13 +82,46 @@ func DescIndex(cli client.Client, args *DescIndexArgs) (*DescIndexResult, error)
 	return result, nil
 }
 
+func ListIndexes(cli client.Client, database, table string) ([]IndexSchema, error) {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getIndexURI())
+	req.SetMethod(http.Post)
+	req.SetParam("list", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, &ListIndexesArgs{Database: database, Table: table})
+	if err != nil {
+		return nil, err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.IsFail() {
+		return nil, resp.ServiceError()
+	}
+	result := &ListIndexesResult{}
+	if err := resp.ParseJSONBody(result); err != nil {
+		return nil, err
+	}
+	return result.Indexes, nil
+}
+
 func ModifyIndex(cli client.Client, args *ModifyIndexArgs) error {
-	req := &client.BceRequest{}
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getIndexURI())
 	req.SetMethod(http.Post)
 	req.SetParam("modify", "")
 
-	jsonBytes, err := sonic.Marshal(args)
+	jsonBytes, err := client.MarshalJSON(cli, args)
 	if err != nil {
 		return err
 	}
@@ -96,7 +131,8 @@ func ModifyIndex(cli client.Client, args *ModifyIndexArgs) error {
 	}
 	req.SetBody(body)
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return err
 	}
@@ -108,14 +144,16 @@ func ModifyIndex(cli client.Client, args *ModifyIndexArgs) error {
 }
 
 func DropIndex(cli client.Client, database, table, indexName string) error {
-	req := &client.BceRequest{}
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getIndexURI())
 	req.SetMethod(http.Delete)
 	req.SetParam("database", database)
 	req.SetParam("table", table)
 	req.SetParam("indexName", indexName)
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return err
 	}
@@ -127,12 +165,13 @@ func DropIndex(cli client.Client, database, table, indexName string) error {
 }
 
 func RebuildIndex(cli client.Client, args *RebuildIndexArgs) error {
-	req := &client.BceRequest{}
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getIndexURI())
 	req.SetMethod(http.Post)
 	req.SetParam("rebuild", "")
 
-	jsonBytes, err := sonic.Marshal(args)
+	jsonBytes, err := client.MarshalJSON(cli, args)
 	if err != nil {
 		return err
 	}
@@ -142,7 +181,8 @@ func RebuildIndex(cli client.Client, args *RebuildIndexArgs) error {
 	}
 	req.SetBody(body)
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return err
 	}
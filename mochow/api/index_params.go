@@ -0,0 +1,308 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// index_params.go - the typed params of the specific index types
+
+package api
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	MinHNSWM              = 4
+	MaxHNSWM              = 128
+	MinHNSWEfConstruction = 8
+	MaxHNSWEfConstruction = 1024
+)
+
+// TypedIndexParams is implemented by the typed params of the index types, the untyped
+// VectorIndexParams can still be set to the IndexSchema for the params not covered by them
+type TypedIndexParams interface {
+	IndexParams() (VectorIndexParams, error)
+}
+
+// SetTypedParams - validate and set the typed params as the Params of the index
+func (s *IndexSchema) SetTypedParams(p TypedIndexParams) error {
+	if p == nil {
+		return errors.New("index params should not be nil")
+	}
+	params, err := p.IndexParams()
+	if err != nil {
+		return fmt.Errorf("invalid params of index %s: %w", s.IndexName, err)
+	}
+	s.Params = params
+	return nil
+}
+
+// HNSWIndexParams defines the construction-time params of the HNSW and BINARY_HNSW indexes
+type HNSWIndexParams struct {
+	// M is the max number of the neighbors of each node, it should be in [4, 128]
+	M uint32
+	// EfConstruction is the size of the dynamic candidate list when building the graph, it
+	// should be in [8, 1024]
+	EfConstruction uint32
+}
+
+// Validate - check the ranges of the params
+func (p *HNSWIndexParams) Validate() error {
+	if p.M < MinHNSWM || p.M > MaxHNSWM {
+		return fmt.Errorf("M %d should be in [%d, %d]", p.M, MinHNSWM, MaxHNSWM)
+	}
+	if p.EfConstruction < MinHNSWEfConstruction || p.EfConstruction > MaxHNSWEfConstruction {
+		return fmt.Errorf("efConstruction %d should be in [%d, %d]", p.EfConstruction,
+			MinHNSWEfConstruction, MaxHNSWEfConstruction)
+	}
+	return nil
+}
+
+// IndexParams - validate and build the params of the IndexSchema
+func (p *HNSWIndexParams) IndexParams() (VectorIndexParams, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return VectorIndexParams{
+		"M":              p.M,
+		"efConstruction": p.EfConstruction,
+	}, nil
+}
+
+// HNSWPQIndexParams defines the construction-time params of the HNSWPQ indexes
+type HNSWPQIndexParams struct {
+	HNSWIndexParams
+	// NSQ is the number of the sub-quantizers, the dimension of the vectors should be a multiple
+	// of it
+	NSQ uint32
+	// SampleRate is the ratio of the rows sampled to train the quantizers, it should be in (0, 1]
+	SampleRate float64
+}
+
+// Validate - check the ranges of the params
+func (p *HNSWPQIndexParams) Validate() error {
+	if err := p.HNSWIndexParams.Validate(); err != nil {
+		return err
+	}
+	if p.NSQ == 0 {
+		return errors.New("NSQ should be positive")
+	}
+	if p.SampleRate <= 0 || p.SampleRate > 1 {
+		return fmt.Errorf("samplerate %v should be in (0, 1]", p.SampleRate)
+	}
+	return nil
+}
+
+// ValidateFor - check the params against the dimension of the vector field
+func (p *HNSWPQIndexParams) ValidateFor(dimension uint32) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if p.NSQ > dimension || dimension%p.NSQ != 0 {
+		return fmt.Errorf("dimension %d should be a multiple of NSQ %d", dimension, p.NSQ)
+	}
+	return nil
+}
+
+// IndexParams - validate and build the params of the IndexSchema
+func (p *HNSWPQIndexParams) IndexParams() (VectorIndexParams, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return VectorIndexParams{
+		"M":              p.M,
+		"efConstruction": p.EfConstruction,
+		"NSQ":            p.NSQ,
+		"samplerate":     p.SampleRate,
+	}, nil
+}
+
+// HNSWPQSearchParams defines the search-time knobs of the HNSWPQ indexes, the zero fields are not
+// sent so that the server defaults are used
+type HNSWPQSearchParams struct {
+	// Ef is the size of the dynamic candidate list of the graph search
+	Ef uint32
+	// RefineFactor re-ranks RefineFactor times of the limit candidates with the original vectors
+	// after the PQ distance ranking, it should be at least 1
+	RefineFactor uint32
+	// PQScanRatio is the ratio of the PQ codes to be scanned, it should be in (0, 1]
+	PQScanRatio float64
+}
+
+// Validate - check the ranges of the params
+func (p *HNSWPQSearchParams) Validate() error {
+	if p.PQScanRatio < 0 || p.PQScanRatio > 1 {
+		return fmt.Errorf("pqScanRatio %v should be in (0, 1]", p.PQScanRatio)
+	}
+	return nil
+}
+
+// Apply - validate and set the params into the search params
+func (p *HNSWPQSearchParams) Apply(params *SearchParams) error {
+	if params == nil {
+		return errors.New("search params should not be nil")
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if p.Ef > 0 {
+		params.AddEf(p.Ef)
+	}
+	if p.RefineFactor > 0 {
+		params.set("refineFactor", p.RefineFactor)
+	}
+	if p.PQScanRatio > 0 {
+		params.set("pqScanRatio", p.PQScanRatio)
+	}
+	return nil
+}
+
+// SearchParams - validate and build the search params
+func (p *HNSWPQSearchParams) SearchParams() (*SearchParams, error) {
+	params := NewSearchParams()
+	if err := p.Apply(params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// PUCKIndexParams defines the construction-time params of the PUCK indexes
+type PUCKIndexParams struct {
+	// CoarseClusterCount is the number of the coarse clusters, it should be positive
+	CoarseClusterCount uint32
+	// FineClusterCount is the number of the fine clusters in each coarse cluster, it should be
+	// positive
+	FineClusterCount uint32
+}
+
+// Validate - check the ranges of the params
+func (p *PUCKIndexParams) Validate() error {
+	if p.CoarseClusterCount == 0 || p.FineClusterCount == 0 {
+		return errors.New("coarseClusterCount and fineClusterCount should be positive")
+	}
+	return nil
+}
+
+// IndexParams - validate and build the params of the IndexSchema
+func (p *PUCKIndexParams) IndexParams() (VectorIndexParams, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return VectorIndexParams{
+		"coarseClusterCount": p.CoarseClusterCount,
+		"fineClusterCount":   p.FineClusterCount,
+	}, nil
+}
+
+// PUCKSearchParams defines the search-time knobs of the PUCK indexes, the zero fields are not sent
+// so that the server defaults are used
+type PUCKSearchParams struct {
+	// SearchCoarseCount is the number of the nearest coarse clusters to be searched, it should not
+	// exceed the CoarseClusterCount of the index
+	SearchCoarseCount uint32
+	// FilterTopK is the number of the candidates kept by the filtering before the re-ranking
+	FilterTopK uint32
+}
+
+// ValidateFor - check the params against the construction-time params of the index
+func (p *PUCKSearchParams) ValidateFor(index *PUCKIndexParams) error {
+	if index != nil && p.SearchCoarseCount > index.CoarseClusterCount {
+		return fmt.Errorf("searchCoarseCount %d exceeds coarseClusterCount %d",
+			p.SearchCoarseCount, index.CoarseClusterCount)
+	}
+	return nil
+}
+
+// Apply - set the params into the search params
+func (p *PUCKSearchParams) Apply(params *SearchParams) error {
+	if params == nil {
+		return errors.New("search params should not be nil")
+	}
+	if p.SearchCoarseCount > 0 {
+		params.AddSearchCoarseCount(p.SearchCoarseCount)
+	}
+	if p.FilterTopK > 0 {
+		params.set("filterTopk", p.FilterTopK)
+	}
+	return nil
+}
+
+// SearchParams - build the search params
+func (p *PUCKSearchParams) SearchParams() (*SearchParams, error) {
+	params := NewSearchParams()
+	if err := p.Apply(params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// InvertedIndexParams defines the analyzer of the INVERTED indexes, the server default analyzer
+// is used if Analyzer is empty
+type InvertedIndexParams struct {
+	Analyzer InvertedIndexAnalyzer
+	// ParseMode is the granularity of the chinese segmentation, it is only used by the
+	// CHINESE_ANALYZER and DEFAULT_ANALYZER
+	ParseMode InvertedIndexParseMode
+}
+
+// Validate - check the analyzer and the parse mode
+func (p *InvertedIndexParams) Validate() error {
+	switch p.Analyzer {
+	case "", EnglishAnalyzer, ChineseAnalyzer, DefaultAnalyzer:
+	default:
+		return fmt.Errorf("unknown analyzer %s", p.Analyzer)
+	}
+	switch p.ParseMode {
+	case "":
+	case CoarseMode, FineMode:
+		if p.Analyzer == EnglishAnalyzer {
+			return fmt.Errorf("parseMode is not supported by %s", p.Analyzer)
+		}
+	default:
+		return fmt.Errorf("unknown parseMode %s", p.ParseMode)
+	}
+	return nil
+}
+
+// ValidateFor - check the analyzer against the type of the text field, the TEXT_GBK and
+// TEXT_GB18030 fields should be analyzed by the CHINESE_ANALYZER
+func (p *InvertedIndexParams) ValidateFor(fieldType FieldType) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	switch fieldType {
+	case FieldTypeText:
+	case FieldTypeTextGBK, FieldTypeTextGB18030:
+		if len(p.Analyzer) != 0 && p.Analyzer != ChineseAnalyzer {
+			return fmt.Errorf("analyzer of the %s field should be %s", fieldType, ChineseAnalyzer)
+		}
+	default:
+		return fmt.Errorf("inverted index can not be built on the %s field", fieldType)
+	}
+	return nil
+}
+
+// IndexParams - validate and build the params of the IndexSchema
+func (p *InvertedIndexParams) IndexParams() (VectorIndexParams, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	params := VectorIndexParams{}
+	if len(p.Analyzer) != 0 {
+		params["analyzer"] = p.Analyzer
+	}
+	if len(p.ParseMode) != 0 {
+		params["parseMode"] = p.ParseMode
+	}
+	return params, nil
+}
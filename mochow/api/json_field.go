@@ -0,0 +1,84 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// json_field.go - the accessors of the nested values of the JSON fields
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// GetPath - get the nested value of the JSON field by the dotted path, e.g. "attrs.size.width" is
+// the "width" of the "size" of the JSON field "attrs"
+//
+// PARAMS:
+//   - path: the field name followed by the keys of the nested objects
+//
+// RETURNS:
+//   - interface{}: the nested value
+//   - bool: false if any object along the path is missing
+func (d *Row) GetPath(path string) (interface{}, bool) {
+	keys := strings.Split(path, ".")
+	value, ok := d.Fields[keys[0]]
+	for _, key := range keys[1:] {
+		if !ok {
+			return nil, false
+		}
+		object, isObject := value.(map[string]interface{})
+		if !isObject {
+			return nil, false
+		}
+		value, ok = object[key]
+	}
+	return value, ok
+}
+
+// SetPath - set the nested value of the JSON field by the dotted path, the missing objects along
+// the path are created
+//
+// PARAMS:
+//   - path: the field name followed by the keys of the nested objects
+//   - value: the value to set
+//
+// RETURNS:
+//   - error: nil if ok, otherwise a value along the path is not an object
+func (d *Row) SetPath(path string, value interface{}) error {
+	if len(path) == 0 {
+		return errors.New("path should not be empty")
+	}
+	if d.Fields == nil {
+		d.Fields = make(map[string]interface{})
+	}
+	keys := strings.Split(path, ".")
+	object := d.Fields
+	for i, key := range keys[:len(keys)-1] {
+		next, ok := object[key]
+		if !ok || next == nil {
+			child := make(map[string]interface{})
+			object[key] = child
+			object = child
+			continue
+		}
+		child, ok := next.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s of %T is not an object", strings.Join(keys[:i+1], "."), next)
+		}
+		object = child
+	}
+	object[keys[len(keys)-1]] = value
+	return nil
+}
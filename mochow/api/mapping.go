@@ -0,0 +1,377 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// mapping.go - the mapping between the go structs and the rows by the struct tags
+
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RowTagName is the struct tag key of the field names, e.g. `mochow:"bookName,omitempty"`. The
+// go field name is used if the tag is missing and the field is skipped if the tag is "-".
+const RowTagName = "mochow"
+
+type structField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+var structFieldsCache sync.Map // reflect.Type -> []structField
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func structFields(t reflect.Type) []structField {
+	if cached, ok := structFieldsCache.Load(t); ok {
+		return cached.([]structField)
+	}
+	var fields []structField
+	collectStructFields(t, nil, &fields)
+	structFieldsCache.Store(t, fields)
+	return fields
+}
+
+// collectStructFields - list the mapped fields of the struct, the untagged embedded structs are
+// flattened
+func collectStructFields(t reflect.Type, index []int, fields *[]structField) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get(RowTagName)
+		if tag == "-" {
+			continue
+		}
+		name, options := tag, ""
+		if comma := strings.IndexByte(tag, ','); comma >= 0 {
+			name, options = tag[:comma], tag[comma+1:]
+		}
+		fieldIndex := append(append([]int(nil), index...), i)
+		if f.Anonymous && len(name) == 0 && f.Type.Kind() == reflect.Struct {
+			collectStructFields(f.Type, fieldIndex, fields)
+			continue
+		}
+		if len(f.PkgPath) != 0 {
+			continue
+		}
+		if len(name) == 0 {
+			name = f.Name
+		}
+		*fields = append(*fields, structField{
+			name:      name,
+			index:     fieldIndex,
+			omitEmpty: strings.Contains(","+options+",", ",omitempty,"),
+		})
+	}
+}
+
+// StructToRow - convert the struct to the row by the struct tags, the nil pointers are null and
+// the zero values of the omitempty fields are skipped
+//
+// PARAMS:
+//   - v: the struct or the pointer to the struct
+//
+// RETURNS:
+//   - Row: the converted row
+//   - error: nil if ok otherwise the specific error
+func StructToRow(v interface{}) (Row, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return Row{}, errors.New("struct should not be nil")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return Row{}, fmt.Errorf("expect a struct but got %T", v)
+	}
+	fields := structFields(rv.Type())
+	row := Row{Fields: make(map[string]interface{}, len(fields))}
+	for _, f := range fields {
+		fv := rv.FieldByIndex(f.index)
+		if f.omitEmpty && fv.IsZero() {
+			continue
+		}
+		for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Ptr {
+			row.Fields[f.name] = nil
+			continue
+		}
+		row.Fields[f.name] = fv.Interface()
+	}
+	return row, nil
+}
+
+// StructsToRows - convert the slice of the structs or the pointers to the structs to the rows
+//
+// PARAMS:
+//   - v: the slice of the structs or the pointers to the structs
+//
+// RETURNS:
+//   - []Row: the converted rows
+//   - error: nil if ok otherwise the specific error
+func StructsToRows(v interface{}) ([]Row, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("expect a slice of structs but got %T", v)
+	}
+	rows := make([]Row, rv.Len())
+	for i := range rows {
+		row, err := StructToRow(rv.Index(i).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("convert row %d failed: %w", i, err)
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
+
+// Decode - decode the fields of the row into the struct by the struct tags, the numbers are
+// coerced to the types of the struct fields and the fields missing in the row are kept as is
+//
+// PARAMS:
+//   - out: the pointer to the struct
+//
+// RETURNS:
+//   - error: nil if ok otherwise the specific error
+func (d *Row) Decode(out interface{}) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("expect a non-nil pointer to a struct but got %T", out)
+	}
+	return decodeFields(d.Fields, rv.Elem())
+}
+
+// DecodeRows - decode the rows into the slice of the structs or the pointers to the structs
+//
+// PARAMS:
+//   - rows: the rows to decode
+//   - out: the pointer to the slice, the slice is replaced by the decoded rows
+//
+// RETURNS:
+//   - error: nil if ok otherwise the specific error
+func DecodeRows(rows []Row, out interface{}) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("expect a non-nil pointer to a slice but got %T", out)
+	}
+	sliceType := rv.Elem().Type()
+	elemType := sliceType.Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("expect a slice of structs but got %s", sliceType)
+	}
+	result := reflect.MakeSlice(sliceType, len(rows), len(rows))
+	for i := range rows {
+		elem := result.Index(i)
+		if elemType.Kind() == reflect.Ptr {
+			elem.Set(reflect.New(structType))
+			elem = elem.Elem()
+		}
+		if err := decodeFields(rows[i].Fields, elem); err != nil {
+			return fmt.Errorf("decode row %d failed: %w", i, err)
+		}
+	}
+	rv.Elem().Set(result)
+	return nil
+}
+
+// DecodeRows - decode the Rows of the multi-key query into the slice of the structs
+func (r *QueryRowResult) DecodeRows(out interface{}) error {
+	return DecodeRows(r.Rows, out)
+}
+
+// DecodeRows - decode the rows of the search results into the slice of the structs, the distances
+// are not decoded
+func (r *SearchRowResult) DecodeRows(out interface{}) error {
+	rows := make([]Row, len(r.Rows))
+	for i := range r.Rows {
+		rows[i] = r.Rows[i].Row
+	}
+	return DecodeRows(rows, out)
+}
+
+// DecodeRows - decode the selected rows into the slice of the structs
+func (r *SelectRowResult) DecodeRows(out interface{}) error {
+	return DecodeRows(r.Rows, out)
+}
+
+func decodeFields(fields map[string]interface{}, rv reflect.Value) error {
+	for _, f := range structFields(rv.Type()) {
+		value, ok := fields[f.name]
+		if !ok {
+			continue
+		}
+		if err := setValue(rv.FieldByIndex(f.index), value); err != nil {
+			return fmt.Errorf("decode field %s failed: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+// setValue - set the decoded value to dst, the json.Number and the other numbers are converted
+// to the numeric kind of dst if the value fits
+func setValue(dst reflect.Value, value interface{}) error {
+	if value == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	src := reflect.ValueOf(value)
+	if src.Type().AssignableTo(dst.Type()) {
+		dst.Set(src)
+		return nil
+	}
+	if dst.Type() == timeType {
+		t, ok := toTime(value)
+		if !ok {
+			return fmt.Errorf("cannot convert %T to %s", value, dst.Type())
+		}
+		dst.Set(reflect.ValueOf(t))
+		return nil
+	}
+	switch dst.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(dst.Type().Elem())
+		if err := setValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	case reflect.Bool:
+		if src.Kind() == reflect.Bool {
+			dst.SetBool(src.Bool())
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, ok := toInt64(value); ok && !dst.OverflowInt(n) {
+			dst.SetInt(n)
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, ok := toUint64(value); ok && !dst.OverflowUint(n) {
+			dst.SetUint(n)
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		if n, ok := toFloat64(value); ok && !dst.OverflowFloat(n) {
+			dst.SetFloat(n)
+			return nil
+		}
+	case reflect.String:
+		if src.Kind() == reflect.String {
+			dst.SetString(src.String())
+			return nil
+		}
+	case reflect.Slice:
+		if dst.Type().Elem().Kind() == reflect.Uint8 && src.Kind() == reflect.String {
+			data, err := base64.StdEncoding.DecodeString(src.String())
+			if err != nil {
+				return err
+			}
+			dst.SetBytes(data)
+			return nil
+		}
+		if src.Kind() == reflect.Slice || src.Kind() == reflect.Array {
+			n := src.Len()
+			slice := reflect.MakeSlice(dst.Type(), n, n)
+			for i := 0; i < n; i++ {
+				if err := setValue(slice.Index(i), src.Index(i).Interface()); err != nil {
+					return fmt.Errorf("element %d: %w", i, err)
+				}
+			}
+			dst.Set(slice)
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot convert %T to %s", value, dst.Type())
+}
+
+func toInt64(value interface{}) (int64, bool) {
+	if n, ok := value.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			return i, true
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return 0, false
+		}
+		value = f
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint()), v.Uint() <= math.MaxInt64
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		return int64(f), f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64
+	}
+	return 0, false
+}
+
+func toUint64(value interface{}) (uint64, bool) {
+	if n, ok := value.(json.Number); ok {
+		if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+			return u, true
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return 0, false
+		}
+		value = f
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return uint64(v.Int()), v.Int() >= 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), true
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		return uint64(f), f == math.Trunc(f) && f >= 0 && f < math.MaxUint64
+	}
+	return 0, false
+}
+
+func toFloat64(value interface{}) (float64, bool) {
+	if n, ok := value.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
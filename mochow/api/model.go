@@ -16,6 +16,16 @@
 
 package api
 
+import (
+	"errors"
+	"fmt"
+)
+
+// ProjectAll is the projection of all the fields. Besides the field names, the projections can be
+// the wildcards like "meta.*" to project the fields with the prefix, e.g. the dynamic fields which
+// can not be enumerated up front.
+const ProjectAll = "*"
+
 type CreateDatabaseArgs struct {
 	Database string `json:"database"`
 }
@@ -24,6 +34,169 @@ type ListDatabaseResult struct {
 	Databases []string `json:"databases,omitempty"`
 }
 
+type DescDatabaseArgs struct {
+	Database string `json:"database"`
+}
+
+type DatabaseDescription struct {
+	Database   string            `json:"database"`
+	CreateTime string            `json:"createTime"`
+	TableCount uint32            `json:"tableCount"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type DescDatabaseResult struct {
+	Database *DatabaseDescription `json:"database,omitempty"`
+}
+
+// ShowDatabaseStatsResult is the sum of the stats of the tables of the database, Tables are the
+// stats of each table
+type ShowDatabaseStatsResult struct {
+	TableCount       uint32                           `json:"tableCount"`
+	RowCount         uint64                           `json:"rowCount"`
+	MemorySizeInByte uint64                           `json:"memorySizeInByte"`
+	DiskSizeInByte   uint64                           `json:"diskSizeInByte"`
+	Tables           map[string]*ShowTableStatsResult `json:"tables,omitempty"`
+}
+
+// Task is the background task of a long running operation, e.g. the rebuilding of an index
+type Task struct {
+	TaskID   string    `json:"taskId"`
+	TaskType TaskType  `json:"taskType"`
+	State    TaskState `json:"state"`
+	Database string    `json:"database,omitempty"`
+	Table    string    `json:"table,omitempty"`
+	// Progress is the ratio of the work done in [0, 1]
+	Progress   float64 `json:"progress"`
+	CreateTime string  `json:"createTime,omitempty"`
+	UpdateTime string  `json:"updateTime,omitempty"`
+	// Message is the reason of the failure if any
+	Message string `json:"message,omitempty"`
+}
+
+// IsDone returns true if the task succeeded, failed or was canceled
+func (t *Task) IsDone() bool {
+	switch t.State {
+	case TaskStateSucceeded, TaskStateFailed, TaskStateCanceled:
+		return true
+	}
+	return false
+}
+
+type DescTaskArgs struct {
+	TaskID string `json:"taskId"`
+}
+
+type DescTaskResult struct {
+	Task *Task `json:"task,omitempty"`
+}
+
+// ListTasksArgs lists the tasks matching all the non-empty conditions
+type ListTasksArgs struct {
+	Database string    `json:"database,omitempty"`
+	Table    string    `json:"table,omitempty"`
+	TaskType TaskType  `json:"taskType,omitempty"`
+	State    TaskState `json:"state,omitempty"`
+}
+
+type ListTasksResult struct {
+	Tasks []Task `json:"tasks,omitempty"`
+}
+
+type CancelTaskArgs struct {
+	TaskID string `json:"taskId"`
+}
+
+// NodeInfo is a node of the cluster, Version is the version of the server running on it
+type NodeInfo struct {
+	NodeID    string    `json:"nodeId"`
+	Address   string    `json:"address"`
+	Role      NodeRole  `json:"role"`
+	Version   string    `json:"version"`
+	State     NodeState `json:"state"`
+	StartTime string    `json:"startTime,omitempty"`
+}
+
+// DescClusterResult is the overview of the cluster, Version is the version of the cluster which is
+// the lowest version of the nodes during the rolling upgrades
+type DescClusterResult struct {
+	ClusterID     string `json:"clusterId"`
+	Version       string `json:"version"`
+	NodeCount     uint32 `json:"nodeCount"`
+	HealthyNodes  uint32 `json:"healthyNodeCount"`
+	DatabaseCount uint32 `json:"databaseCount"`
+}
+
+type ListNodesResult struct {
+	Nodes []NodeInfo `json:"nodes,omitempty"`
+}
+
+// Unhealthy returns the nodes which are not healthy
+func (r *ListNodesResult) Unhealthy() []NodeInfo {
+	var nodes []NodeInfo
+	for _, node := range r.Nodes {
+		if node.State != NodeStateHealthy {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+type CreateUserArgs struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type ChangePasswordArgs struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type ListUsersResult struct {
+	Users []string `json:"users,omitempty"`
+}
+
+type CreateRoleArgs struct {
+	Role string `json:"role"`
+}
+
+type ListRolesResult struct {
+	Roles []string `json:"roles,omitempty"`
+}
+
+type GrantRoleArgs struct {
+	Username string   `json:"username"`
+	Roles    []string `json:"roles"`
+}
+
+type RevokeRoleArgs struct {
+	Username string   `json:"username"`
+	Roles    []string `json:"roles"`
+}
+
+// PrivilegeTuple is the privileges on the objects, the Database and Table "*" mean all, e.g.
+// {Database: "db", Table: "*"} is all the tables of the database db
+type PrivilegeTuple struct {
+	Database   string      `json:"database"`
+	Table      string      `json:"table"`
+	Privileges []Privilege `json:"privileges"`
+}
+
+// GrantPrivilegeArgs grants the privileges to the user or the role, either Username or Role is set
+type GrantPrivilegeArgs struct {
+	Username        string           `json:"username,omitempty"`
+	Role            string           `json:"role,omitempty"`
+	PrivilegeTuples []PrivilegeTuple `json:"privilegeTuples"`
+}
+
+// RevokePrivilegeArgs revokes the privileges from the user or the role, either Username or Role
+// is set
+type RevokePrivilegeArgs struct {
+	Username        string           `json:"username,omitempty"`
+	Role            string           `json:"role,omitempty"`
+	PrivilegeTuples []PrivilegeTuple `json:"privilegeTuples"`
+}
+
 type CreateTableArgs struct {
 	Database           string           `json:"database"`
 	Table              string           `json:"table"`
@@ -32,6 +205,12 @@ type CreateTableArgs struct {
 	Partition          *PartitionParams `json:"partition,omitempty"`
 	EnableDynamicField bool             `json:"enableDynamicField,omitempty"`
 	Schema             *TableSchema     `json:"schema,omitempty"`
+	// TTLField is the DATETIME or TIMESTAMP field the expiration of each row counts from, the
+	// rows expire TTLSeconds after their last writes if it is empty
+	TTLField string `json:"ttlField,omitempty"`
+	// TTLSeconds expires the rows TTLSeconds after the time of the TTLField, the rows never expire
+	// if it is zero unless the writes set their TTLSeconds
+	TTLSeconds uint64 `json:"ttlSeconds,omitempty"`
 }
 
 type ListTableArgs struct {
@@ -57,6 +236,16 @@ type AddFieldArgs struct {
 	Schema   *TableSchema `json:"schema,omitempty"`
 }
 
+// ModifyTableArgs changes the mutable properties of the table, the nil or zero properties are
+// unchanged
+type ModifyTableArgs struct {
+	Database           string  `json:"database"`
+	Table              string  `json:"table"`
+	Description        *string `json:"description,omitempty"`
+	Replication        uint32  `json:"replication,omitempty"`
+	EnableDynamicField *bool   `json:"enableDynamicField,omitempty"`
+}
+
 type AliasTableArgs struct {
 	Database string `json:"database"`
 	Table    string `json:"table"`
@@ -69,6 +258,43 @@ type UnaliasTableArgs struct {
 	Alias    string `json:"alias"`
 }
 
+// ListAliasesArgs lists the aliases of the database, or of the table if Table is set
+type ListAliasesArgs struct {
+	Database string `json:"database"`
+	Table    string `json:"table,omitempty"`
+}
+
+// AliasInfo is an alias and the table it points at
+type AliasInfo struct {
+	Alias string `json:"alias"`
+	Table string `json:"table"`
+}
+
+type ListAliasesResult struct {
+	Aliases []AliasInfo `json:"aliases,omitempty"`
+}
+
+// TableAliases returns the aliases pointing at the table
+func (r *ListAliasesResult) TableAliases(table string) []string {
+	var aliases []string
+	for _, alias := range r.Aliases {
+		if alias.Table == table {
+			aliases = append(aliases, alias.Alias)
+		}
+	}
+	return aliases
+}
+
+type DescAliasArgs struct {
+	Database string `json:"database"`
+	Alias    string `json:"alias"`
+}
+
+type DescAliasResult struct {
+	Alias string `json:"alias"`
+	Table string `json:"table"`
+}
+
 type ShowTableStatsArgs struct {
 	Database string `json:"database"`
 	Table    string `json:"table"`
@@ -78,6 +304,91 @@ type ShowTableStatsResult struct {
 	RowCount         uint64 `json:"rowCount"`
 	MemorySizeInByte uint64 `json:"memorySizeInByte"`
 	DiskSizeInByte   uint64 `json:"diskSizeInByte"`
+	// PartitionCount and Partitions are the usage of the partitions, they are empty if the
+	// server does not report them
+	PartitionCount uint32          `json:"partitionCount,omitempty"`
+	Partitions     []PartitionInfo `json:"partitions,omitempty"`
+}
+
+// PartitionInfo is the placement and the usage of a partition of the table, Leader and Replicas
+// are the addresses of the data nodes serving the partition
+type PartitionInfo struct {
+	PartitionID      uint32   `json:"partitionId"`
+	State            string   `json:"state,omitempty"`
+	Leader           string   `json:"leader,omitempty"`
+	Replicas         []string `json:"replicas,omitempty"`
+	RowCount         uint64   `json:"rowCount"`
+	MemorySizeInByte uint64   `json:"memorySizeInByte"`
+	DiskSizeInByte   uint64   `json:"diskSizeInByte"`
+}
+
+type DescPartitionsArgs struct {
+	Database string `json:"database"`
+	Table    string `json:"table"`
+}
+
+type DescPartitionsResult struct {
+	Partitions []PartitionInfo `json:"partitions"`
+}
+
+// Skew returns the ratio of the row count of the largest partition to the average, it is 1 if
+// the rows are evenly distributed and 0 if the table is empty
+func (r *DescPartitionsResult) Skew() float64 {
+	var total, largest uint64
+	for _, partition := range r.Partitions {
+		total += partition.RowCount
+		if partition.RowCount > largest {
+			largest = partition.RowCount
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(largest) * float64(len(r.Partitions)) / float64(total)
+}
+
+type RebalancePartitionsArgs struct {
+	Database string `json:"database"`
+	Table    string `json:"table"`
+}
+
+// ResplitPartitionsArgs redistributes the rows of the table to PartitionNum partitions
+type ResplitPartitionsArgs struct {
+	Database     string `json:"database"`
+	Table        string `json:"table"`
+	PartitionNum uint32 `json:"partitionNum"`
+}
+
+type CompactTableArgs struct {
+	Database string `json:"database"`
+	Table    string `json:"table"`
+}
+
+type FlushTableArgs struct {
+	Database string `json:"database"`
+	Table    string `json:"table"`
+}
+
+// MaintenanceTaskResult is the result of the flush, the compaction and the partition maintenance
+// of the table, TaskID is the id of the background task to wait for by DescTask
+type MaintenanceTaskResult struct {
+	TaskID string `json:"taskId"`
+}
+
+type ShowStorageStatusArgs struct {
+	Database string `json:"database"`
+	Table    string `json:"table"`
+}
+
+// ShowStorageStatusResult is the status of the segments of the table, UnflushedRowCount is the
+// number of rows in the memory which are not flushed to the segments yet
+type ShowStorageStatusResult struct {
+	SegmentCount       uint64 `json:"segmentCount"`
+	UnflushedRowCount  uint64 `json:"unflushedRowCount"`
+	Flushing           bool   `json:"flushing"`
+	Compacting         bool   `json:"compacting"`
+	LastFlushTime      string `json:"lastFlushTime,omitempty"`
+	LastCompactionTime string `json:"lastCompactionTime,omitempty"`
 }
 
 type CreateIndexArgs struct {
@@ -96,6 +407,15 @@ type DescIndexResult struct {
 	Index IndexSchema `json:"index"`
 }
 
+type ListIndexesArgs struct {
+	Database string `json:"database"`
+	Table    string `json:"table"`
+}
+
+type ListIndexesResult struct {
+	Indexes []IndexSchema `json:"indexes,omitempty"`
+}
+
 type ModifyIndexArgs struct {
 	Database string      `json:"database"`
 	Table    string      `json:"table"`
@@ -112,36 +432,91 @@ type InsertRowArgs struct {
 	Database string `json:"database,omitempty"`
 	Table    string `json:"table,omitempty"`
 	Rows     []Row  `json:"rows,omitempty"`
+	// TTLSeconds expires the written rows TTLSeconds after the write, it overrides the TTL of the
+	// table, the TTL of the table applies if zero. The TTLSeconds of a row overrides it for the row.
+	TTLSeconds uint64 `json:"ttlSeconds,omitempty"`
 }
 
 type InsertRowResult struct {
 	AffectedCount uint64 `json:"affectedCount"`
+	// PrimaryKeys are the primary keys of the written rows in the order of the rows, including the
+	// values assigned by the server to the auto-increment primary key fields
+	PrimaryKeys []Row `json:"primaryKeys,omitempty"`
+}
+
+// IDs - get the values of the primary key field of the written rows, e.g. the auto-increment ids,
+// the numbers are json.Number
+func (r *InsertRowResult) IDs(field string) []interface{} {
+	ids := make([]interface{}, len(r.PrimaryKeys))
+	for i, primaryKey := range r.PrimaryKeys {
+		ids[i] = primaryKey.Fields[field]
+	}
+	return ids
 }
 
+// UpsertRowArg replaces the whole rows, the fields missing in the rows are not kept, so the vector
+// fields are sent with every upsert, use UpdateRowArgs to change some fields of a row, e.g. the
+// scalar fields without the vectors
 type UpsertRowArg InsertRowArgs
 
 type UpsertRowResult InsertRowResult
 
+// IDs - get the values of the primary key field of the written rows, see InsertRowResult.IDs
+func (r *UpsertRowResult) IDs(field string) []interface{} {
+	return (*InsertRowResult)(r).IDs(field)
+}
+
 type DeleteRowArgs struct {
-	Database     string                 `json:"database"`
-	Table        string                 `json:"table"`
-	PrimaryKey   map[string]interface{} `json:"primaryKey,omitempty"`
-	PartitionKey map[string]interface{} `json:"partitionKey,omitempty"`
-	Filter       string                 `json:"filter,omitempty"`
+	Database   string                 `json:"database"`
+	Table      string                 `json:"table"`
+	PrimaryKey map[string]interface{} `json:"primaryKey,omitempty"`
+	// PrimaryKeys deletes the rows of a set of primary keys in one request instead of PrimaryKey
+	PrimaryKeys  []map[string]interface{} `json:"primaryKeys,omitempty"`
+	PartitionKey map[string]interface{}   `json:"partitionKey,omitempty"`
+	Filter       string                   `json:"filter,omitempty"`
+}
+
+type DeleteRowResult struct {
+	AffectedCount uint64 `json:"affectedCount"`
 }
 
 type QueryRowArgs struct {
-	Database        string                 `json:"database"`
-	Table           string                 `json:"table"`
-	PrimaryKey      map[string]interface{} `json:"primaryKey,omitempty"`
-	PartitionKey    map[string]interface{} `json:"partitionKey,omitempty"`
-	Projections     []string               `json:"projections,omitempty"`
-	RetrieveVector  bool                   `json:"retrieveVector,omitempty"`
-	ReadConsistency ReadConsistency        `json:"readConsistency,omitempty"`
+	Database   string                 `json:"database"`
+	Table      string                 `json:"table"`
+	PrimaryKey map[string]interface{} `json:"primaryKey,omitempty"`
+	// PrimaryKeys queries the rows of a set of primary keys in one request(keys IN (...)) instead
+	// of PrimaryKey, the found rows are returned in the Rows of the result
+	PrimaryKeys     []map[string]interface{} `json:"primaryKeys,omitempty"`
+	PartitionKey    map[string]interface{}   `json:"partitionKey,omitempty"`
+	Projections     []string                 `json:"projections,omitempty"`
+	RetrieveVector  bool                     `json:"retrieveVector,omitempty"`
+	ReadConsistency ReadConsistency          `json:"readConsistency,omitempty"`
+	// ExcludeProjections removes the fields from the projections, all the other fields are
+	// projected if Projections is empty
+	ExcludeProjections []string `json:"excludeProjections,omitempty"`
 }
 
 type QueryRowResult struct {
 	Row Row `json:"row,omitempty"`
+	// Rows are the found rows of the PrimaryKeys, the keys not found are skipped
+	Rows []Row `json:"rows,omitempty"`
+}
+
+// BatchQueryRowArgs queries the rows of multiple primary keys in one request, PartitionKeys are
+// aligned with PrimaryKeys if set
+type BatchQueryRowArgs struct {
+	Database        string                   `json:"database"`
+	Table           string                   `json:"table"`
+	PrimaryKeys     []map[string]interface{} `json:"primaryKeys"`
+	PartitionKeys   []map[string]interface{} `json:"partitionKeys,omitempty"`
+	Projections     []string                 `json:"projections,omitempty"`
+	RetrieveVector  bool                     `json:"retrieveVector,omitempty"`
+	ReadConsistency ReadConsistency          `json:"readConsistency,omitempty"`
+}
+
+// BatchQueryRowResult contains the found rows, the keys not found are skipped
+type BatchQueryRowResult struct {
+	Rows []Row `json:"rows,omitempty"`
 }
 
 type SearchRowArgs struct {
@@ -152,6 +527,15 @@ type SearchRowArgs struct {
 	RetrieveVector  bool                   `json:"retrieveVector,omitempty"`
 	Projections     []string               `json:"projections,omitempty"`
 	ReadConsistency ReadConsistency        `json:"readConsistency,omitempty"`
+	// ExcludeProjections removes the fields from the projections, all the other fields are
+	// projected if Projections is empty
+	ExcludeProjections []string `json:"excludeProjections,omitempty"`
+	// GroupBy groups the rows by the scalar field and keeps the nearest GroupSize rows of each
+	// group, e.g. at most 2 chunks of each document, the limit of the params caps the number of
+	// the groups. The grouped search is sent by GroupSearchRow, the server default GroupSize is
+	// used if zero.
+	GroupBy   string `json:"groupBy,omitempty"`
+	GroupSize uint32 `json:"groupSize,omitempty"`
 }
 
 type RowResult struct {
@@ -164,12 +548,45 @@ type SearchRowResult struct {
 	Rows               []RowResult `json:"rows,omitempty"`
 }
 
+// RowGroup is the rows of the same GroupBy value in the order of their distances
+type RowGroup struct {
+	GroupValue interface{} `json:"groupValue"`
+	Rows       []RowResult `json:"rows,omitempty"`
+}
+
+// GroupSearchRowResult is the groups of the grouped search in the order of their nearest rows
+type GroupSearchRowResult struct {
+	SearchVectorFloats []float32  `json:"searchVectorFloats,omitempty"`
+	Groups             []RowGroup `json:"groups,omitempty"`
+}
+
+// Rows returns the rows of all the groups in turn, e.g. the deduplicated contexts of RAG
+func (r *GroupSearchRowResult) Rows() []RowResult {
+	var n int
+	for _, group := range r.Groups {
+		n += len(group.Rows)
+	}
+	rows := make([]RowResult, 0, n)
+	for _, group := range r.Groups {
+		rows = append(rows, group.Rows...)
+	}
+	return rows
+}
+
+// UpdateRowArgs changes some fields of a row, only the fields in Update are sent and changed, the
+// other fields including the vector fields are kept as is
 type UpdateRowArgs struct {
 	Database     string                 `json:"database"`
 	Table        string                 `json:"table"`
 	PrimaryKey   map[string]interface{} `json:"primaryKey,omitempty"`
 	PartitionKey map[string]interface{} `json:"partitionKey,omitempty"`
 	Update       map[string]interface{} `json:"update,omitempty"`
+	// Filter updates all the rows matching the scalar filter instead of the row of PrimaryKey
+	Filter string `json:"filter,omitempty"`
+}
+
+type UpdateRowResult struct {
+	AffectedCount uint64 `json:"affectedCount"`
 }
 
 type SelectRowArgs struct {
@@ -180,6 +597,19 @@ type SelectRowArgs struct {
 	Limit           uint64                 `json:"limit"`
 	Projections     []string               `json:"projections,omitempty"`
 	ReadConsistency ReadConsistency        `json:"readConsistency,omitempty"`
+	// OrderBy sorts the rows by the fields in turn instead of the primary key order, the
+	// NextMarker of the result continues the listing in the same order
+	OrderBy []OrderByField `json:"orderBy,omitempty"`
+	// ExcludeProjections removes the fields from the projections, all the other fields are
+	// projected if Projections is empty
+	ExcludeProjections []string `json:"excludeProjections,omitempty"`
+	RetrieveVector     bool     `json:"retrieveVector,omitempty"`
+}
+
+// OrderByField sorts the rows by the field, in ascending order if Order is empty
+type OrderByField struct {
+	FieldName string    `json:"fieldName"`
+	Order     SortOrder `json:"order,omitempty"`
 }
 
 type SelectRowResult struct {
@@ -188,6 +618,139 @@ type SelectRowResult struct {
 	Rows        []Row                  `json:"rows,omitempty"`
 }
 
+// HybridSearchRowArgs runs the vector and keyword searches in one request and fuses the results
+type HybridSearchRowArgs struct {
+	Database        string                 `json:"database"`
+	Table           string                 `json:"table"`
+	Hybrid          *HybridSearchParams    `json:"hybrid,omitempty"`
+	PartitionKey    map[string]interface{} `json:"partitionKey,omitempty"`
+	RetrieveVector  bool                   `json:"retrieveVector,omitempty"`
+	Projections     []string               `json:"projections,omitempty"`
+	ReadConsistency ReadConsistency        `json:"readConsistency,omitempty"`
+}
+
+// HybridRowResult is a fused row, Score is the fused score in descending order
+type HybridRowResult struct {
+	Row   Row     `json:"row"`
+	Score float64 `json:"score"`
+}
+
+type HybridSearchRowResult struct {
+	Rows []HybridRowResult `json:"rows,omitempty"`
+}
+
+// TextSearchRowArgs searches the rows by the keywords over the inverted index only
+type TextSearchRowArgs struct {
+	Database        string                 `json:"database"`
+	Table           string                 `json:"table"`
+	BM25            *BM25SearchParams      `json:"bm25,omitempty"`
+	Filter          string                 `json:"filter,omitempty"`
+	Limit           uint32                 `json:"limit,omitempty"`
+	PartitionKey    map[string]interface{} `json:"partitionKey,omitempty"`
+	RetrieveVector  bool                   `json:"retrieveVector,omitempty"`
+	Projections     []string               `json:"projections,omitempty"`
+	ReadConsistency ReadConsistency        `json:"readConsistency,omitempty"`
+}
+
+// TextRowResult is a matched row, Score is the BM25 relevance in descending order
+type TextRowResult struct {
+	Row   Row     `json:"row"`
+	Score float64 `json:"score"`
+}
+
+type TextSearchRowResult struct {
+	Rows []TextRowResult `json:"rows,omitempty"`
+}
+
+// VectorFieldSearch is the ann search of one vector field in the multivector search, Weight is
+// the weight of the field in the WEIGHTED_SUM fusion
+type VectorFieldSearch struct {
+	ANNS   *ANNSearchParams `json:"anns"`
+	Weight float64          `json:"weight,omitempty"`
+}
+
+// MultiVectorSearchArgs searches multiple vector fields of the table in one request and fuses the
+// results into one list, the RRF fusion is used if FusionType is empty
+type MultiVectorSearchArgs struct {
+	Database   string              `json:"database"`
+	Table      string              `json:"table"`
+	Searches   []VectorFieldSearch `json:"searches"`
+	FusionType FusionType          `json:"fusionType,omitempty"`
+	// K is the rank constant of the RRF fusion, the server default is used if zero
+	K               uint32                 `json:"k,omitempty"`
+	Filter          string                 `json:"filter,omitempty"`
+	Limit           uint32                 `json:"limit,omitempty"`
+	PartitionKey    map[string]interface{} `json:"partitionKey,omitempty"`
+	RetrieveVector  bool                   `json:"retrieveVector,omitempty"`
+	Projections     []string               `json:"projections,omitempty"`
+	ReadConsistency ReadConsistency        `json:"readConsistency,omitempty"`
+}
+
+// Validate - check that each search targets a distinct vector field and the weights are
+// consistent with the fusion type
+func (a *MultiVectorSearchArgs) Validate() error {
+	if len(a.Searches) == 0 {
+		return errors.New("searches should not be empty")
+	}
+	fields := make(map[string]bool, len(a.Searches))
+	var totalWeight float64
+	for i, search := range a.Searches {
+		if search.ANNS == nil || len(search.ANNS.VectorField) == 0 {
+			return fmt.Errorf("vector field of search %d should not be empty", i)
+		}
+		if fields[search.ANNS.VectorField] {
+			return fmt.Errorf("vector field %s is searched more than once", search.ANNS.VectorField)
+		}
+		fields[search.ANNS.VectorField] = true
+		if search.Weight < 0 {
+			return fmt.Errorf("weight %v of field %s should be non-negative",
+				search.Weight, search.ANNS.VectorField)
+		}
+		totalWeight += search.Weight
+	}
+	switch a.FusionType {
+	case "", FusionRRF:
+		if totalWeight != 0 {
+			return errors.New("weights are only supported by the WEIGHTED_SUM fusion")
+		}
+	case FusionWeightedSum:
+		if totalWeight == 0 {
+			return errors.New("weights should not be all zero")
+		}
+	default:
+		return fmt.Errorf("unknown fusion type %s", a.FusionType)
+	}
+	return nil
+}
+
+// RangeSearchArgs returns the rows within the distance range of the vector instead of the top k
+// rows. Radius is the outer bound and RangeFilter is the inner bound, i.e. for the L2 metric the
+// rows with RangeFilter <= distance < Radius are returned, and for the IP and COSINE metrics where
+// the larger is closer the rows with Radius < distance <= RangeFilter are returned. The rows are
+// paged by Limit and Marker like SelectRowArgs.
+type RangeSearchArgs struct {
+	Database     string    `json:"database"`
+	Table        string    `json:"table"`
+	VectorField  string    `json:"vectorField"`
+	VectorFloats []float32 `json:"vectorFloats,omitempty"`
+	Radius       float64   `json:"radius"`
+	// RangeFilter is ignored if nil so that all the rows within the radius are returned
+	RangeFilter     *float64               `json:"rangeFilter,omitempty"`
+	Filter          string                 `json:"filter,omitempty"`
+	Limit           uint32                 `json:"limit,omitempty"`
+	Marker          map[string]interface{} `json:"marker,omitempty"`
+	PartitionKey    map[string]interface{} `json:"partitionKey,omitempty"`
+	RetrieveVector  bool                   `json:"retrieveVector,omitempty"`
+	Projections     []string               `json:"projections,omitempty"`
+	ReadConsistency ReadConsistency        `json:"readConsistency,omitempty"`
+}
+
+type RangeSearchResult struct {
+	IsTruncated bool                   `json:"isTruncated"`
+	NextMarker  map[string]interface{} `json:"nextMarker,omitempty"`
+	Rows        []RowResult            `json:"rows,omitempty"`
+}
+
 type BatchSearchRowArgs struct {
 	Database        string                 `json:"database"`
 	Table           string                 `json:"table"`
@@ -200,3 +763,44 @@ type BatchSearchRowArgs struct {
 type BatchSearchRowResult struct {
 	Results []SearchRowResult `json:"results,omitempty"`
 }
+
+// Aggregation computes the function over the scalar field, the FieldName of COUNT can be empty to
+// count the rows. The value is named by Alias, or the function and field like "SUM(price)" if empty.
+type Aggregation struct {
+	Func      AggregateFunc `json:"func"`
+	FieldName string        `json:"fieldName,omitempty"`
+	Alias     string        `json:"alias,omitempty"`
+}
+
+// Name - get the name of the aggregated value in the AggregateGroup
+func (a Aggregation) Name() string {
+	if len(a.Alias) != 0 {
+		return a.Alias
+	}
+	if len(a.FieldName) == 0 {
+		return string(a.Func) + "(*)"
+	}
+	return string(a.Func) + "(" + a.FieldName + ")"
+}
+
+type AggregateArgs struct {
+	Database     string        `json:"database"`
+	Table        string        `json:"table"`
+	Filter       string        `json:"filter,omitempty"`
+	Aggregations []Aggregation `json:"aggregations"`
+	// GroupBy groups the rows by the scalar fields, all the rows are aggregated in one group if
+	// empty
+	GroupBy         []string        `json:"groupBy,omitempty"`
+	ReadConsistency ReadConsistency `json:"readConsistency,omitempty"`
+}
+
+// AggregateGroup is the aggregated values of a group, Keys are the values of the GroupBy fields
+// and Values are keyed by the names of the aggregations
+type AggregateGroup struct {
+	Keys   map[string]interface{} `json:"keys,omitempty"`
+	Values map[string]interface{} `json:"values,omitempty"`
+}
+
+type AggregateResult struct {
+	Groups []AggregateGroup `json:"groups,omitempty"`
+}
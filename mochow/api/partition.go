@@ -0,0 +1,277 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// partition.go - the boundaries of the RANGE and LIST partitions and their validation
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+// RangePartition is a partition of the RANGE partitioned table, it holds the rows whose partition
+// keys are less than UpperBound and not less than the UpperBound of the previous partition. The
+// UpperBound of the last partition may be nil to hold all the greater keys.
+//
+// The bounds of the DATE, DATETIME and TIMESTAMP partition keys are the strings formatted by
+// FormatTime, the bounds of the numeric partition keys are the numbers.
+type RangePartition struct {
+	PartitionName string      `json:"partitionName"`
+	UpperBound    interface{} `json:"upperBound,omitempty"`
+}
+
+// ListPartition is a partition of the LIST partitioned table, it holds the rows whose partition
+// keys equal one of the Values, a value belongs to at most one partition
+type ListPartition struct {
+	PartitionName string        `json:"partitionName"`
+	Values        []interface{} `json:"values"`
+}
+
+// MarshalJSON - marshal the params with the PartitionNum of the RANGE and LIST partitions set to
+// the number of their partitions if it is zero
+func (p *PartitionParams) MarshalJSON() ([]byte, error) {
+	type params PartitionParams
+	aux := params(*p)
+	if aux.PartitionNum == 0 {
+		switch aux.PartitionType {
+		case RANGE:
+			aux.PartitionNum = uint32(len(aux.Ranges))
+		case LIST:
+			aux.PartitionNum = uint32(len(aux.Lists))
+		}
+	}
+	return sonic.Marshal(&aux)
+}
+
+// ValidateFor - check the partitions against the type of the partition key, i.e. the RANGE
+// partitions are non-empty with the increasing bounds of a numeric or time key, and the values of
+// the LIST partitions are non-empty and not duplicated
+//
+// PARAMS:
+//   - keyType: the type of the partition key field
+//
+// RETURNS:
+//   - error: nil if ok otherwise the first invalid partition
+func (p *PartitionParams) ValidateFor(keyType FieldType) error {
+	switch p.PartitionType {
+	case "", HASH:
+		if len(p.Ranges) != 0 || len(p.Lists) != 0 {
+			return errors.New("ranges and lists are not supported by HASH partition")
+		}
+		return nil
+	case RANGE:
+		if len(p.Lists) != 0 {
+			return errors.New("lists are not supported by RANGE partition")
+		}
+		return p.validateRanges(keyType)
+	case LIST:
+		if len(p.Ranges) != 0 {
+			return errors.New("ranges are not supported by LIST partition")
+		}
+		return p.validateLists(keyType)
+	}
+	return fmt.Errorf("unknown partition type %s", p.PartitionType)
+}
+
+func (p *PartitionParams) validateRanges(keyType FieldType) error {
+	if len(p.Ranges) == 0 {
+		return errors.New("ranges of RANGE partition should not be empty")
+	}
+	if p.PartitionNum != 0 && int(p.PartitionNum) != len(p.Ranges) {
+		return fmt.Errorf("partitionNum %d does not match %d ranges", p.PartitionNum,
+			len(p.Ranges))
+	}
+	if !isNumericField(keyType) && !IsTimeField(keyType) {
+		return fmt.Errorf("RANGE partition key should be numeric or time, got %s", keyType)
+	}
+	names := make(map[string]struct{}, len(p.Ranges))
+	var last interface{}
+	for i, r := range p.Ranges {
+		if err := checkPartitionName(names, r.PartitionName); err != nil {
+			return err
+		}
+		if r.UpperBound == nil {
+			if i != len(p.Ranges)-1 {
+				return fmt.Errorf("upper bound of partition %s should not be nil", r.PartitionName)
+			}
+			continue
+		}
+		bound, err := partitionValue(keyType, r.UpperBound)
+		if err != nil {
+			return fmt.Errorf("invalid upper bound of partition %s: %w", r.PartitionName, err)
+		}
+		if last != nil && comparePartitionValues(last, bound) >= 0 {
+			return fmt.Errorf("upper bound of partition %s should be greater than the previous",
+				r.PartitionName)
+		}
+		last = bound
+	}
+	return nil
+}
+
+func (p *PartitionParams) validateLists(keyType FieldType) error {
+	if len(p.Lists) == 0 {
+		return errors.New("lists of LIST partition should not be empty")
+	}
+	if p.PartitionNum != 0 && int(p.PartitionNum) != len(p.Lists) {
+		return fmt.Errorf("partitionNum %d does not match %d lists", p.PartitionNum, len(p.Lists))
+	}
+	if !isNumericField(keyType) && !IsTimeField(keyType) && keyType != FieldTypeString &&
+		keyType != FieldTypeBool {
+		return fmt.Errorf("LIST partition key should be numeric, time, string or bool, got %s",
+			keyType)
+	}
+	names := make(map[string]struct{}, len(p.Lists))
+	values := make(map[string]string)
+	for _, l := range p.Lists {
+		if err := checkPartitionName(names, l.PartitionName); err != nil {
+			return err
+		}
+		if len(l.Values) == 0 {
+			return fmt.Errorf("values of partition %s should not be empty", l.PartitionName)
+		}
+		for _, value := range l.Values {
+			v, err := partitionValue(keyType, value)
+			if err != nil {
+				return fmt.Errorf("invalid value of partition %s: %w", l.PartitionName, err)
+			}
+			key := partitionValueKey(v)
+			if name, ok := values[key]; ok {
+				return fmt.Errorf("value %v of partition %s is duplicated in partition %s",
+					value, l.PartitionName, name)
+			}
+			values[key] = l.PartitionName
+		}
+	}
+	return nil
+}
+
+func checkPartitionName(names map[string]struct{}, name string) error {
+	if len(name) == 0 {
+		return errors.New("partition name should not be empty")
+	}
+	if _, ok := names[name]; ok {
+		return fmt.Errorf("partition %s is duplicated", name)
+	}
+	names[name] = struct{}{}
+	return nil
+}
+
+func isNumericField(fieldType FieldType) bool {
+	switch fieldType {
+	case FieldTypeInt8, FieldTypeUint8, FieldTypeInt16, FieldTypeUint16, FieldTypeInt32,
+		FieldTypeUint32, FieldTypeInt64, FieldTypeUint64, FieldTypeFloat, FieldTypeDouble:
+		return true
+	}
+	return false
+}
+
+// partitionValue - convert the bound or the value of the partition to the comparable value, i.e.
+// *big.Rat of the numbers, time.Time of the times, and string or bool as is
+func partitionValue(keyType FieldType, value interface{}) (interface{}, error) {
+	switch {
+	case isNumericField(keyType):
+		return partitionNumber(value)
+	case IsTimeField(keyType):
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expect a string of %s but got %T", keyType, value)
+		}
+		return ParseTime(keyType, s)
+	case keyType == FieldTypeString:
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+	case keyType == FieldTypeBool:
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported value %T of %s", value, keyType)
+}
+
+func partitionNumber(value interface{}) (*big.Rat, error) {
+	r := new(big.Rat)
+	switch v := value.(type) {
+	case int:
+		return r.SetInt64(int64(v)), nil
+	case int8:
+		return r.SetInt64(int64(v)), nil
+	case int16:
+		return r.SetInt64(int64(v)), nil
+	case int32:
+		return r.SetInt64(int64(v)), nil
+	case int64:
+		return r.SetInt64(v), nil
+	case uint:
+		return r.SetUint64(uint64(v)), nil
+	case uint8:
+		return r.SetUint64(uint64(v)), nil
+	case uint16:
+		return r.SetUint64(uint64(v)), nil
+	case uint32:
+		return r.SetUint64(uint64(v)), nil
+	case uint64:
+		return r.SetUint64(v), nil
+	case float32:
+		if r.SetFloat64(float64(v)) == nil {
+			return nil, fmt.Errorf("invalid number %v", v)
+		}
+		return r, nil
+	case float64:
+		if r.SetFloat64(v) == nil {
+			return nil, fmt.Errorf("invalid number %v", v)
+		}
+		return r, nil
+	case json.Number:
+		if _, ok := r.SetString(string(v)); !ok {
+			return nil, fmt.Errorf("invalid number %q", string(v))
+		}
+		return r, nil
+	}
+	return nil, fmt.Errorf("expect a number but got %T", value)
+}
+
+// comparePartitionValues - compare the values converted by partitionValue of the same key type
+func comparePartitionValues(a, b interface{}) int {
+	switch x := a.(type) {
+	case *big.Rat:
+		return x.Cmp(b.(*big.Rat))
+	case time.Time:
+		y := b.(time.Time)
+		switch {
+		case x.Before(y):
+			return -1
+		case x.After(y):
+			return 1
+		}
+	}
+	return 0
+}
+
+func partitionValueKey(v interface{}) string {
+	switch x := v.(type) {
+	case *big.Rat:
+		return x.RatString()
+	case time.Time:
+		return x.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// read_options.go - the functional options of the simple read requests
+
+package api
+
+// ReadOptions are the common options of the query, select and search requests. The defaults are
+// all the scalar fields projected, the vectors not retrieved and the EVENTUAL consistency.
+type ReadOptions struct {
+	Projections     []string
+	RetrieveVector  bool
+	ReadConsistency ReadConsistency
+	PartitionKey    map[string]interface{}
+}
+
+// ReadOption sets an option of the read request
+type ReadOption func(*ReadOptions)
+
+// NewReadOptions - apply the options to the default read options
+func NewReadOptions(opts ...ReadOption) *ReadOptions {
+	options := &ReadOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// WithReadConsistency - read with the consistency, e.g. STRONG to see the latest writes
+func WithReadConsistency(consistency ReadConsistency) ReadOption {
+	return func(o *ReadOptions) {
+		o.ReadConsistency = consistency
+	}
+}
+
+// WithProjections - return only the fields, the primary key fields are always returned
+func WithProjections(fields ...string) ReadOption {
+	return func(o *ReadOptions) {
+		o.Projections = append(o.Projections, fields...)
+	}
+}
+
+// WithRetrieveVector - return the vector fields as well
+func WithRetrieveVector(retrieve bool) ReadOption {
+	return func(o *ReadOptions) {
+		o.RetrieveVector = retrieve
+	}
+}
+
+// WithPartitionKey - read only the partition of the key, it is ignored by the select
+func WithPartitionKey(partitionKey map[string]interface{}) ReadOption {
+	return func(o *ReadOptions) {
+		o.PartitionKey = partitionKey
+	}
+}
@@ -17,29 +17,36 @@
 package api
 
 import (
-	"github.com/bytedance/sonic"
-
 	"github.com/baidu/mochow-sdk-go/client"
 	"github.com/baidu/mochow-sdk-go/http"
 )
 
 func InsertRow(cli client.Client, args *InsertRowArgs) (*InsertRowResult, error) {
-	req := &client.BceRequest{}
+	if groups := groupRowsByTTL(args); groups != nil {
+		return insertRowGroups(cli, args, groups)
+	}
+
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getRowURI())
 	req.SetMethod(http.Post)
 	req.SetParam("insert", "")
 
-	jsonBytes, err := sonic.Marshal(args)
+	jsonBytes, err := marshalRowArgs(cli, args)
 	if err != nil {
 		return nil, err
 	}
+	if exceedPayloadSize(cli, jsonBytes) && len(args.Rows) > 1 {
+		return splitInsertRow(cli, args)
+	}
 	body, err := client.NewBodyFromBytes(jsonBytes)
 	if err != nil {
 		return nil, err
 	}
 	req.SetBody(body)
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return nil, err
 	}
@@ -54,22 +61,31 @@ func InsertRow(cli client.Client, args *InsertRowArgs) (*InsertRowResult, error)
 }
 
 func UpsertRow(cli client.Client, args *UpsertRowArg) (*UpsertRowResult, error) {
-	req := &client.BceRequest{}
+	if groups := groupRowsByTTL((*InsertRowArgs)(args)); groups != nil {
+		return upsertRowGroups(cli, args, groups)
+	}
+
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getRowURI())
 	req.SetMethod(http.Post)
 	req.SetParam("upsert", "")
 
-	jsonBytes, err := sonic.Marshal(args)
+	jsonBytes, err := marshalRowArgs(cli, (*InsertRowArgs)(args))
 	if err != nil {
 		return nil, err
 	}
+	if exceedPayloadSize(cli, jsonBytes) && len(args.Rows) > 1 {
+		return splitUpsertRow(cli, args)
+	}
 	body, err := client.NewBodyFromBytes(jsonBytes)
 	if err != nil {
 		return nil, err
 	}
 	req.SetBody(body)
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return nil, err
 	}
@@ -84,12 +100,13 @@ func UpsertRow(cli client.Client, args *UpsertRowArg) (*UpsertRowResult, error)
 }
 
 func DeleteRow(cli client.Client, args *DeleteRowArgs) error {
-	req := &client.BceRequest{}
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getRowURI())
 	req.SetMethod(http.Post)
 	req.SetParam("delete", "")
 
-	jsonBytes, err := sonic.Marshal(args)
+	jsonBytes, err := client.MarshalJSON(cli, args)
 	if err != nil {
 		return err
 	}
@@ -99,7 +116,8 @@ func DeleteRow(cli client.Client, args *DeleteRowArgs) error {
 	}
 	req.SetBody(body)
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return err
 	}
@@ -110,13 +128,47 @@ func DeleteRow(cli client.Client, args *DeleteRowArgs) error {
 	return nil
 }
 
+// DeleteRows - delete the rows like DeleteRow and return the number of the deleted rows
+func DeleteRows(cli client.Client, args *DeleteRowArgs) (*DeleteRowResult, error) {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getRowURI())
+	req.SetMethod(http.Post)
+	req.SetParam("delete", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return nil, err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.IsFail() {
+		return nil, resp.ServiceError()
+	}
+	result := &DeleteRowResult{}
+	if err := resp.ParseJSONBody(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func QueryRow(cli client.Client, args *QueryRowArgs) (*QueryRowResult, error) {
-	req := &client.BceRequest{}
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getRowURI())
 	req.SetMethod(http.Post)
 	req.SetParam("query", "")
 
-	jsonBytes, err := sonic.Marshal(args)
+	jsonBytes, err := client.MarshalJSON(cli, args)
 	if err != nil {
 		return nil, err
 	}
@@ -126,7 +178,8 @@ func QueryRow(cli client.Client, args *QueryRowArgs) (*QueryRowResult, error) {
 	}
 	req.SetBody(body)
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return nil, err
 	}
@@ -140,13 +193,46 @@ func QueryRow(cli client.Client, args *QueryRowArgs) (*QueryRowResult, error) {
 	return result, nil
 }
 
+func BatchQueryRow(cli client.Client, args *BatchQueryRowArgs) (*BatchQueryRowResult, error) {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getRowURI())
+	req.SetMethod(http.Post)
+	req.SetParam("batchQuery", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return nil, err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.IsFail() {
+		return nil, resp.ServiceError()
+	}
+	result := &BatchQueryRowResult{}
+	if err := resp.ParseJSONBody(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func SearchRow(cli client.Client, args *SearchRowArgs) (*SearchRowResult, error) {
-	req := &client.BceRequest{}
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getRowURI())
 	req.SetMethod(http.Post)
 	req.SetParam("search", "")
 
-	jsonBytes, err := sonic.Marshal(args)
+	jsonBytes, err := client.MarshalJSON(cli, args)
 	if err != nil {
 		return nil, err
 	}
@@ -156,7 +242,8 @@ func SearchRow(cli client.Client, args *SearchRowArgs) (*SearchRowResult, error)
 	}
 	req.SetBody(body)
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return nil, err
 	}
@@ -170,13 +257,46 @@ func SearchRow(cli client.Client, args *SearchRowArgs) (*SearchRowResult, error)
 	return result, nil
 }
 
+func GroupSearchRow(cli client.Client, args *SearchRowArgs) (*GroupSearchRowResult, error) {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getRowURI())
+	req.SetMethod(http.Post)
+	req.SetParam("search", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return nil, err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.IsFail() {
+		return nil, resp.ServiceError()
+	}
+	result := &GroupSearchRowResult{}
+	if err := resp.ParseJSONBody(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func UpdateRow(cli client.Client, args *UpdateRowArgs) error {
-	req := &client.BceRequest{}
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getRowURI())
 	req.SetMethod(http.Post)
 	req.SetParam("update", "")
 
-	jsonBytes, err := sonic.Marshal(args)
+	jsonBytes, err := client.MarshalJSON(cli, args)
 	if err != nil {
 		return err
 	}
@@ -186,7 +306,8 @@ func UpdateRow(cli client.Client, args *UpdateRowArgs) error {
 	}
 	req.SetBody(body)
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return err
 	}
@@ -197,13 +318,47 @@ func UpdateRow(cli client.Client, args *UpdateRowArgs) error {
 	return nil
 }
 
+// UpdateRows - update the rows like UpdateRow and return the number of the updated rows
+func UpdateRows(cli client.Client, args *UpdateRowArgs) (*UpdateRowResult, error) {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getRowURI())
+	req.SetMethod(http.Post)
+	req.SetParam("update", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return nil, err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.IsFail() {
+		return nil, resp.ServiceError()
+	}
+	result := &UpdateRowResult{}
+	if err := resp.ParseJSONBody(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func SelectRow(cli client.Client, args *SelectRowArgs) (*SelectRowResult, error) {
-	req := &client.BceRequest{}
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getRowURI())
 	req.SetMethod(http.Post)
 	req.SetParam("select", "")
 
-	jsonBytes, err := sonic.Marshal(args)
+	jsonBytes, err := client.MarshalJSON(cli, args)
 	if err != nil {
 		return nil, err
 	}
@@ -213,7 +368,8 @@ func SelectRow(cli client.Client, args *SelectRowArgs) (*SelectRowResult, error)
 	}
 	req.SetBody(body)
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return nil, err
 	}
@@ -227,23 +383,188 @@ func SelectRow(cli client.Client, args *SelectRowArgs) (*SelectRowResult, error)
 	return result, nil
 }
 
+func Aggregate(cli client.Client, args *AggregateArgs) (*AggregateResult, error) {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getRowURI())
+	req.SetMethod(http.Post)
+	req.SetParam("aggregate", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return nil, err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.IsFail() {
+		return nil, resp.ServiceError()
+	}
+	result := &AggregateResult{}
+	if err := resp.ParseJSONBody(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func HybridSearchRow(cli client.Client, args *HybridSearchRowArgs) (*HybridSearchRowResult, error) {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getRowURI())
+	req.SetMethod(http.Post)
+	req.SetParam("hybridSearch", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return nil, err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.IsFail() {
+		return nil, resp.ServiceError()
+	}
+	result := &HybridSearchRowResult{}
+	if err := resp.ParseJSONBody(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func TextSearchRow(cli client.Client, args *TextSearchRowArgs) (*TextSearchRowResult, error) {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getRowURI())
+	req.SetMethod(http.Post)
+	req.SetParam("bm25Search", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return nil, err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.IsFail() {
+		return nil, resp.ServiceError()
+	}
+	result := &TextSearchRowResult{}
+	if err := resp.ParseJSONBody(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func MultiVectorSearchRow(cli client.Client, args *MultiVectorSearchArgs) (*SearchRowResult, error) {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getRowURI())
+	req.SetMethod(http.Post)
+	req.SetParam("multiVectorSearch", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return nil, err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.IsFail() {
+		return nil, resp.ServiceError()
+	}
+	result := &SearchRowResult{}
+	if err := resp.ParseJSONBody(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func RangeSearchRow(cli client.Client, args *RangeSearchArgs) (*RangeSearchResult, error) {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getRowURI())
+	req.SetMethod(http.Post)
+	req.SetParam("rangeSearch", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return nil, err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.IsFail() {
+		return nil, resp.ServiceError()
+	}
+	result := &RangeSearchResult{}
+	if err := resp.ParseJSONBody(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func BatchSearchRow(cli client.Client, args *BatchSearchRowArgs) (*BatchSearchRowResult, error) {
-	req := &client.BceRequest{}
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getRowURI())
 	req.SetMethod(http.Post)
 	req.SetParam("batchSearch", "")
 
-	jsonBytes, err := sonic.Marshal(args)
+	jsonBytes, err := client.MarshalJSON(cli, args)
 	if err != nil {
 		return nil, err
 	}
+	if exceedPayloadSize(cli, jsonBytes) && args.ANNS != nil && len(args.ANNS.VectorFloats) > 1 {
+		return splitBatchSearchRow(cli, args)
+	}
 	body, err := client.NewBodyFromBytes(jsonBytes)
 	if err != nil {
 		return nil, err
 	}
 	req.SetBody(body)
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return nil, err
 	}
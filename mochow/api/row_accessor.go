@@ -0,0 +1,168 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// row_accessor.go - the typed getters of the fields of the rows
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// DateLayout is the layout of the DATE fields
+	DateLayout = "2006-01-02"
+	// DatetimeLayout is the layout of the DATETIME and TIMESTAMP fields
+	DatetimeLayout = "2006-01-02 15:04:05"
+)
+
+// ErrFieldNotFound is returned by the getters of the Row when the field is missing or null
+var ErrFieldNotFound = errors.New("field not found")
+
+// FieldTypeError is returned by the getters of the Row when the value of the field can not be
+// converted to the type
+type FieldTypeError struct {
+	Field string
+	Value interface{}
+	// Type is the expected go type, e.g. "int64"
+	Type string
+}
+
+func (e *FieldTypeError) Error() string {
+	return fmt.Sprintf("cannot convert field %s of %T to %s", e.Field, e.Value, e.Type)
+}
+
+func (d *Row) get(field string) (interface{}, error) {
+	value, ok := d.Fields[field]
+	if !ok || value == nil {
+		return nil, fmt.Errorf("%w: %s", ErrFieldNotFound, field)
+	}
+	return value, nil
+}
+
+// GetString - get the string field
+func (d *Row) GetString(field string) (string, error) {
+	value, err := d.get(field)
+	if err != nil {
+		return "", err
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", &FieldTypeError{Field: field, Value: value, Type: "string"}
+	}
+	return s, nil
+}
+
+// GetInt64 - get the integer field, the json.Number and the other numbers are converted if the
+// value is an integer in the range of int64
+func (d *Row) GetInt64(field string) (int64, error) {
+	value, err := d.get(field)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := toInt64(value)
+	if !ok {
+		return 0, &FieldTypeError{Field: field, Value: value, Type: "int64"}
+	}
+	return n, nil
+}
+
+// GetFloat64 - get the numeric field
+func (d *Row) GetFloat64(field string) (float64, error) {
+	value, err := d.get(field)
+	if err != nil {
+		return 0, err
+	}
+	f, ok := toFloat64(value)
+	if !ok {
+		return 0, &FieldTypeError{Field: field, Value: value, Type: "float64"}
+	}
+	return f, nil
+}
+
+// GetBool - get the bool field
+func (d *Row) GetBool(field string) (bool, error) {
+	value, err := d.get(field)
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, &FieldTypeError{Field: field, Value: value, Type: "bool"}
+	}
+	return b, nil
+}
+
+// GetFloatVector - get the FLOAT_VECTOR field, e.g. the decoded json array of the numbers
+func (d *Row) GetFloatVector(field string) ([]float32, error) {
+	value, err := d.get(field)
+	if err != nil {
+		return nil, err
+	}
+	switch v := value.(type) {
+	case []float32:
+		return v, nil
+	case []float64:
+		vector := make([]float32, len(v))
+		for i := range v {
+			vector[i] = float32(v[i])
+		}
+		return vector, nil
+	case []interface{}:
+		vector := make([]float32, len(v))
+		for i := range v {
+			f, ok := toFloat64(v[i])
+			if !ok {
+				return nil, &FieldTypeError{Field: field, Value: value, Type: "[]float32"}
+			}
+			vector[i] = float32(f)
+		}
+		return vector, nil
+	}
+	return nil, &FieldTypeError{Field: field, Value: value, Type: "[]float32"}
+}
+
+// GetTime - get the DATE, DATETIME or TIMESTAMP field, the strings are parsed in UTC by
+// DatetimeLayout, DateLayout or RFC 3339 and the numbers are the unix seconds
+func (d *Row) GetTime(field string) (time.Time, error) {
+	value, err := d.get(field)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, ok := toTime(value)
+	if !ok {
+		return time.Time{}, &FieldTypeError{Field: field, Value: value, Type: "time.Time"}
+	}
+	return t, nil
+}
+
+func toTime(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		for _, layout := range []string{DatetimeLayout, DateLayout, time.RFC3339Nano} {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, true
+			}
+		}
+		return time.Time{}, false
+	}
+	if seconds, ok := toInt64(value); ok {
+		return time.Unix(seconds, 0).UTC(), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// split.go - split the requests exceeding the max payload size into multiple requests
+
+package api
+
+import (
+	"github.com/baidu/mochow-sdk-go/client"
+	"github.com/baidu/mochow-sdk-go/util/log"
+)
+
+// exceedPayloadSize - check whether the marshaled body exceeds the max payload size of the client
+func exceedPayloadSize(cli client.Client, body []byte) bool {
+	maxSize := int64(client.DefaultMaxPayloadSizeInBytes)
+	if conf := cli.GetBceClientConfig(); conf != nil && conf.MaxPayloadSizeInBytes != 0 {
+		maxSize = conf.MaxPayloadSizeInBytes
+	}
+	if maxSize < 0 || int64(len(body)) <= maxSize {
+		return false
+	}
+	log.Infof("request body size %d exceeds max payload size %d, split it", len(body), maxSize)
+	return true
+}
+
+// splitInsertRow - insert the two halves of the rows separately and aggregate the results, the
+// rows of the first half are kept inserted if the second half fails.
+func splitInsertRow(cli client.Client, args *InsertRowArgs) (*InsertRowResult, error) {
+	half := len(args.Rows) / 2
+	first, second := *args, *args
+	first.Rows, second.Rows = args.Rows[:half], args.Rows[half:]
+
+	result, err := InsertRow(cli, &first)
+	if err != nil {
+		return nil, err
+	}
+	secondResult, err := InsertRow(cli, &second)
+	if err != nil {
+		return nil, err
+	}
+	result.AffectedCount += secondResult.AffectedCount
+	result.PrimaryKeys = append(result.PrimaryKeys, secondResult.PrimaryKeys...)
+	return result, nil
+}
+
+// splitUpsertRow - upsert the two halves of the rows separately and aggregate the results, the
+// rows of the first half are kept upserted if the second half fails.
+func splitUpsertRow(cli client.Client, args *UpsertRowArg) (*UpsertRowResult, error) {
+	half := len(args.Rows) / 2
+	first, second := *args, *args
+	first.Rows, second.Rows = args.Rows[:half], args.Rows[half:]
+
+	result, err := UpsertRow(cli, &first)
+	if err != nil {
+		return nil, err
+	}
+	secondResult, err := UpsertRow(cli, &second)
+	if err != nil {
+		return nil, err
+	}
+	result.AffectedCount += secondResult.AffectedCount
+	result.PrimaryKeys = append(result.PrimaryKeys, secondResult.PrimaryKeys...)
+	return result, nil
+}
+
+// splitBatchSearchRow - search the two halves of the vectors separately and merge the results in
+// the order of the vectors.
+func splitBatchSearchRow(cli client.Client, args *BatchSearchRowArgs) (*BatchSearchRowResult, error) {
+	half := len(args.ANNS.VectorFloats) / 2
+	first, second := *args, *args
+	firstANNS, secondANNS := *args.ANNS, *args.ANNS
+	firstANNS.VectorFloats = args.ANNS.VectorFloats[:half]
+	secondANNS.VectorFloats = args.ANNS.VectorFloats[half:]
+	first.ANNS, second.ANNS = &firstANNS, &secondANNS
+
+	result, err := BatchSearchRow(cli, &first)
+	if err != nil {
+		return nil, err
+	}
+	secondResult, err := BatchSearchRow(cli, &second)
+	if err != nil {
+		return nil, err
+	}
+	result.Results = append(result.Results, secondResult.Results...)
+	return result, nil
+}
+
+// rowTTLGroup is the rows of one TTL, by their indexes in the args
+type rowTTLGroup struct {
+	ttlSeconds uint64
+	indexes    []int
+}
+
+// groupRowsByTTL - group the rows by their TTLs, i.e. the TTLSeconds of the row if positive
+// otherwise of the args, nil if all the rows are of the TTLSeconds of the args
+func groupRowsByTTL(args *InsertRowArgs) []rowTTLGroup {
+	var groups []rowTTLGroup
+	positions := make(map[uint64]int)
+	for i := range args.Rows {
+		ttl := args.Rows[i].TTLSeconds
+		if ttl == 0 {
+			ttl = args.TTLSeconds
+		}
+		pos, ok := positions[ttl]
+		if !ok {
+			pos = len(groups)
+			positions[ttl] = pos
+			groups = append(groups, rowTTLGroup{ttlSeconds: ttl})
+		}
+		groups[pos].indexes = append(groups[pos].indexes, i)
+	}
+	if len(groups) == 1 && groups[0].ttlSeconds == args.TTLSeconds {
+		return nil
+	}
+	return groups
+}
+
+// writeRowGroups - write the rows of each TTL group by one request of the TTL and aggregate the
+// results in the order of the rows, the rows of the previous groups are kept written if a group
+// fails. The primary keys are dropped if any group does not return one for each row.
+func writeRowGroups(args *InsertRowArgs, groups []rowTTLGroup,
+	write func(args *InsertRowArgs) (*InsertRowResult, error)) (*InsertRowResult, error) {
+	result := &InsertRowResult{PrimaryKeys: make([]Row, len(args.Rows))}
+	for _, group := range groups {
+		groupArgs := *args
+		groupArgs.TTLSeconds = group.ttlSeconds
+		groupArgs.Rows = make([]Row, len(group.indexes))
+		for i, index := range group.indexes {
+			groupArgs.Rows[i] = args.Rows[index]
+		}
+		groupResult, err := write(&groupArgs)
+		if err != nil {
+			return nil, err
+		}
+		result.AffectedCount += groupResult.AffectedCount
+		if result.PrimaryKeys == nil || len(groupResult.PrimaryKeys) != len(group.indexes) {
+			result.PrimaryKeys = nil
+			continue
+		}
+		for i, index := range group.indexes {
+			result.PrimaryKeys[index] = groupResult.PrimaryKeys[i]
+		}
+	}
+	return result, nil
+}
+
+// insertRowGroups - insert the rows of each TTL group separately, see writeRowGroups
+func insertRowGroups(cli client.Client, args *InsertRowArgs,
+	groups []rowTTLGroup) (*InsertRowResult, error) {
+	return writeRowGroups(args, groups, func(args *InsertRowArgs) (*InsertRowResult, error) {
+		return InsertRow(cli, args)
+	})
+}
+
+// upsertRowGroups - upsert the rows of each TTL group separately, see writeRowGroups
+func upsertRowGroups(cli client.Client, args *UpsertRowArg,
+	groups []rowTTLGroup) (*UpsertRowResult, error) {
+	result, err := writeRowGroups((*InsertRowArgs)(args), groups,
+		func(args *InsertRowArgs) (*InsertRowResult, error) {
+			result, err := UpsertRow(cli, (*UpsertRowArg)(args))
+			return (*InsertRowResult)(result), err
+		})
+	return (*UpsertRowResult)(result), err
+}
@@ -17,18 +17,17 @@
 package api
 
 import (
-	"github.com/bytedance/sonic"
-
 	"github.com/baidu/mochow-sdk-go/client"
 	"github.com/baidu/mochow-sdk-go/http"
 )
 
 func CreateTable(cli client.Client, args *CreateTableArgs) error {
-	req := &client.BceRequest{}
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getTableURI())
 	req.SetMethod(http.Post)
 	req.SetParam("create", "")
-	jsonBytes, err := sonic.Marshal(args)
+	jsonBytes, err := marshalCreateTableArgs(cli, args)
 	if err != nil {
 		return err
 	}
@@ -38,7 +37,8 @@ func CreateTable(cli client.Client, args *CreateTableArgs) error {
 	}
 	req.SetBody(body)
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return err
 	}
@@ -50,13 +50,15 @@ func CreateTable(cli client.Client, args *CreateTableArgs) error {
 }
 
 func DropTable(cli client.Client, database, table string) error {
-	req := &client.BceRequest{}
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getTableURI())
 	req.SetMethod(http.Delete)
 	req.SetParam("database", database)
 	req.SetParam("table", table)
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return err
 	}
@@ -68,12 +70,13 @@ func DropTable(cli client.Client, database, table string) error {
 }
 
 func ListTable(cli client.Client, args *ListTableArgs) (*ListTableResult, error) {
-	req := &client.BceRequest{}
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getTableURI())
 	req.SetMethod(http.Post)
 	req.SetParam("list", "")
 
-	jsonBytes, err := sonic.Marshal(args)
+	jsonBytes, err := client.MarshalJSON(cli, args)
 	if err != nil {
 		return nil, err
 	}
@@ -83,7 +86,8 @@ func ListTable(cli client.Client, args *ListTableArgs) (*ListTableResult, error)
 	}
 	req.SetBody(body)
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return nil, err
 	}
@@ -98,12 +102,13 @@ func ListTable(cli client.Client, args *ListTableArgs) (*ListTableResult, error)
 }
 
 func DescTable(cli client.Client, args *DescTableArgs) (*DescTableResult, error) {
-	req := &client.BceRequest{}
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getTableURI())
 	req.SetMethod(http.Post)
 	req.SetParam("desc", "")
 
-	jsonBytes, err := sonic.Marshal(args)
+	jsonBytes, err := client.MarshalJSON(cli, args)
 	if err != nil {
 		return nil, err
 	}
@@ -113,7 +118,8 @@ func DescTable(cli client.Client, args *DescTableArgs) (*DescTableResult, error)
 	}
 	req.SetBody(body)
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return nil, err
 	}
@@ -128,12 +134,13 @@ func DescTable(cli client.Client, args *DescTableArgs) (*DescTableResult, error)
 }
 
 func AddField(cli client.Client, args *AddFieldArgs) error {
-	req := &client.BceRequest{}
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getTableURI())
 	req.SetMethod(http.Post)
 	req.SetParam("addField", "")
 
-	jsonBytes, err := sonic.Marshal(args)
+	jsonBytes, err := marshalAddFieldArgs(cli, args)
 	if err != nil {
 		return err
 	}
@@ -143,7 +150,37 @@ func AddField(cli client.Client, args *AddFieldArgs) error {
 	}
 	req.SetBody(body)
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return err
+	}
+	if resp.IsFail() {
+		return resp.ServiceError()
+	}
+	defer func() { resp.Body().Close() }()
+	return nil
+}
+
+func ModifyTable(cli client.Client, args *ModifyTableArgs) error {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getTableURI())
+	req.SetMethod(http.Post)
+	req.SetParam("modify", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return err
 	}
@@ -155,12 +192,13 @@ func AddField(cli client.Client, args *AddFieldArgs) error {
 }
 
 func AliasTable(cli client.Client, args *AliasTableArgs) error {
-	req := &client.BceRequest{}
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getTableURI())
 	req.SetMethod(http.Post)
 	req.SetParam("alias", "")
 
-	jsonBytes, err := sonic.Marshal(args)
+	jsonBytes, err := client.MarshalJSON(cli, args)
 	if err != nil {
 		return err
 	}
@@ -170,7 +208,8 @@ func AliasTable(cli client.Client, args *AliasTableArgs) error {
 	}
 	req.SetBody(body)
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return err
 	}
@@ -182,12 +221,13 @@ func AliasTable(cli client.Client, args *AliasTableArgs) error {
 }
 
 func UnaliasTable(cli client.Client, args *UnaliasTableArgs) error {
-	req := &client.BceRequest{}
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getTableURI())
 	req.SetMethod(http.Post)
 	req.SetParam("unalias", "")
 
-	jsonBytes, err := sonic.Marshal(args)
+	jsonBytes, err := client.MarshalJSON(cli, args)
 	if err != nil {
 		return err
 	}
@@ -197,7 +237,8 @@ func UnaliasTable(cli client.Client, args *UnaliasTableArgs) error {
 	}
 	req.SetBody(body)
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return err
 	}
@@ -208,13 +249,78 @@ func UnaliasTable(cli client.Client, args *UnaliasTableArgs) error {
 	return nil
 }
 
+func ListAliases(cli client.Client, args *ListAliasesArgs) (*ListAliasesResult, error) {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getTableURI())
+	req.SetMethod(http.Post)
+	req.SetParam("listAlias", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return nil, err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.IsFail() {
+		return nil, resp.ServiceError()
+	}
+	result := &ListAliasesResult{}
+	if err := resp.ParseJSONBody(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func DescAlias(cli client.Client, args *DescAliasArgs) (*DescAliasResult, error) {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getTableURI())
+	req.SetMethod(http.Post)
+	req.SetParam("descAlias", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return nil, err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.IsFail() {
+		return nil, resp.ServiceError()
+	}
+	result := &DescAliasResult{}
+	if err := resp.ParseJSONBody(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func ShowTableStats(cli client.Client, args *ShowTableStatsArgs) (*ShowTableStatsResult, error) {
-	req := &client.BceRequest{}
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
 	req.SetURI(getTableURI())
 	req.SetMethod(http.Post)
 	req.SetParam("stats", "")
 
-	jsonBytes, err := sonic.Marshal(args)
+	jsonBytes, err := client.MarshalJSON(cli, args)
 	if err != nil {
 		return nil, err
 	}
@@ -224,7 +330,8 @@ func ShowTableStats(cli client.Client, args *ShowTableStatsArgs) (*ShowTableStat
 	}
 	req.SetBody(body)
 
-	resp := &client.BceResponse{}
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
 	if err := cli.SendRequest(req, resp); err != nil {
 		return nil, err
 	}
@@ -237,3 +344,195 @@ func ShowTableStats(cli client.Client, args *ShowTableStatsArgs) (*ShowTableStat
 	}
 	return result, nil
 }
+
+func CompactTable(cli client.Client, args *CompactTableArgs) (*MaintenanceTaskResult, error) {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getTableURI())
+	req.SetMethod(http.Post)
+	req.SetParam("compact", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return nil, err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.IsFail() {
+		return nil, resp.ServiceError()
+	}
+	result := &MaintenanceTaskResult{}
+	if err := resp.ParseJSONBody(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func FlushTable(cli client.Client, args *FlushTableArgs) (*MaintenanceTaskResult, error) {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getTableURI())
+	req.SetMethod(http.Post)
+	req.SetParam("flush", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return nil, err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.IsFail() {
+		return nil, resp.ServiceError()
+	}
+	result := &MaintenanceTaskResult{}
+	if err := resp.ParseJSONBody(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func ShowStorageStatus(cli client.Client, args *ShowStorageStatusArgs) (*ShowStorageStatusResult, error) {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getTableURI())
+	req.SetMethod(http.Post)
+	req.SetParam("storageStatus", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return nil, err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.IsFail() {
+		return nil, resp.ServiceError()
+	}
+	result := &ShowStorageStatusResult{}
+	if err := resp.ParseJSONBody(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func DescPartitions(cli client.Client, args *DescPartitionsArgs) (*DescPartitionsResult, error) {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getTableURI())
+	req.SetMethod(http.Post)
+	req.SetParam("descPartition", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return nil, err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.IsFail() {
+		return nil, resp.ServiceError()
+	}
+	result := &DescPartitionsResult{}
+	if err := resp.ParseJSONBody(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func RebalancePartitions(cli client.Client, args *RebalancePartitionsArgs) (*MaintenanceTaskResult, error) {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getTableURI())
+	req.SetMethod(http.Post)
+	req.SetParam("rebalancePartition", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return nil, err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.IsFail() {
+		return nil, resp.ServiceError()
+	}
+	result := &MaintenanceTaskResult{}
+	if err := resp.ParseJSONBody(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func ResplitPartitions(cli client.Client, args *ResplitPartitionsArgs) (*MaintenanceTaskResult, error) {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getTableURI())
+	req.SetMethod(http.Post)
+	req.SetParam("resplitPartition", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return nil, err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.IsFail() {
+		return nil, resp.ServiceError()
+	}
+	result := &MaintenanceTaskResult{}
+	if err := resp.ParseJSONBody(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// time.go - the conversions between time.Time and the DATE, DATETIME and TIMESTAMP fields
+
+package api
+
+import (
+	"fmt"
+	"time"
+)
+
+// IsTimeField returns true if the field type is DATE, DATETIME or TIMESTAMP
+func IsTimeField(fieldType FieldType) bool {
+	return fieldType == FieldTypeDate || fieldType == FieldTypeDatetime ||
+		fieldType == FieldTypeTimestamp
+}
+
+// FormatTime - format the time as the value of the field type, the DATE and DATETIME values are
+// the wall clock of t, the TIMESTAMP values are in UTC, and t is formatted in RFC 3339 for the
+// other types
+func FormatTime(fieldType FieldType, t time.Time) string {
+	switch fieldType {
+	case FieldTypeDate:
+		return t.Format(DateLayout)
+	case FieldTypeDatetime:
+		return t.Format(DatetimeLayout)
+	case FieldTypeTimestamp:
+		return t.UTC().Format(DatetimeLayout)
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// ParseTime - parse the value of the DATE, DATETIME or TIMESTAMP field in UTC
+func ParseTime(fieldType FieldType, value string) (time.Time, error) {
+	switch fieldType {
+	case FieldTypeDate:
+		return time.Parse(DateLayout, value)
+	case FieldTypeDatetime, FieldTypeTimestamp:
+		return time.Parse(DatetimeLayout, value)
+	}
+	return time.Time{}, fmt.Errorf("%s is not a time field type", fieldType)
+}
+
+// HasTimes returns true if any field of the row is a time.Time or *time.Time
+func (d *Row) HasTimes() bool {
+	for _, value := range d.Fields {
+		switch value.(type) {
+		case time.Time, *time.Time:
+			return true
+		}
+	}
+	return false
+}
+
+// EncodeTimes - format the time.Time values of the row by the types of the fields in the schema,
+// see FormatTime, the nil *time.Time values are null
+//
+// PARAMS:
+//   - schema: the schema of the table
+//
+// RETURNS:
+//   - Row: the row with the formatted values, the fields are copied if any value is formatted
+func (d *Row) EncodeTimes(schema *TableSchema) Row {
+	if !d.HasTimes() {
+		return *d
+	}
+	fieldTypes := schemaFieldTypes(schema)
+	fields := make(map[string]interface{}, len(d.Fields))
+	for name, value := range d.Fields {
+		switch t := value.(type) {
+		case time.Time:
+			value = FormatTime(fieldTypes[name], t)
+		case *time.Time:
+			value = nil
+			if t != nil {
+				value = FormatTime(fieldTypes[name], *t)
+			}
+		}
+		fields[name] = value
+	}
+	return Row{Fields: fields, TTLSeconds: d.TTLSeconds}
+}
+
+// DecodeTimes - parse the string values of the DATE, DATETIME and TIMESTAMP fields of the schema
+// into time.Time in place, see ParseTime
+//
+// PARAMS:
+//   - schema: the schema of the table
+//
+// RETURNS:
+//   - error: nil if ok otherwise the value can not be parsed
+func (d *Row) DecodeTimes(schema *TableSchema) error {
+	for name, fieldType := range schemaFieldTypes(schema) {
+		s, ok := d.Fields[name].(string)
+		if !ok || !IsTimeField(fieldType) {
+			continue
+		}
+		t, err := ParseTime(fieldType, s)
+		if err != nil {
+			return fmt.Errorf("decode field %s failed: %w", name, err)
+		}
+		d.Fields[name] = t
+	}
+	return nil
+}
+
+func schemaFieldTypes(schema *TableSchema) map[string]FieldType {
+	if schema == nil {
+		return nil
+	}
+	fieldTypes := make(map[string]FieldType, len(schema.Fields))
+	for _, field := range schema.Fields {
+		fieldTypes[field.FieldName] = field.FieldType
+	}
+	return fieldTypes
+}
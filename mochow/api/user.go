@@ -0,0 +1,315 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// user.go - the user, role and privilege APIs definition supported by the Mochow service
+
+package api
+
+import (
+	"github.com/baidu/mochow-sdk-go/client"
+	"github.com/baidu/mochow-sdk-go/http"
+)
+
+func CreateUser(cli client.Client, args *CreateUserArgs) error {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getUserURI())
+	req.SetMethod(http.Post)
+	req.SetParam("create", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return err
+	}
+	if resp.IsFail() {
+		return resp.ServiceError()
+	}
+	defer func() { resp.Body().Close() }()
+	return nil
+}
+
+func DropUser(cli client.Client, username string) error {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getUserURI())
+	req.SetMethod(http.Delete)
+	req.SetParam("username", username)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return err
+	}
+	if resp.IsFail() {
+		return resp.ServiceError()
+	}
+	defer func() { resp.Body().Close() }()
+	return nil
+}
+
+func ChangePassword(cli client.Client, args *ChangePasswordArgs) error {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getUserURI())
+	req.SetMethod(http.Post)
+	req.SetParam("changePassword", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return err
+	}
+	if resp.IsFail() {
+		return resp.ServiceError()
+	}
+	defer func() { resp.Body().Close() }()
+	return nil
+}
+
+func ListUsers(cli client.Client) (*ListUsersResult, error) {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getUserURI())
+	req.SetMethod(http.Post)
+	req.SetParam("list", "")
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.IsFail() {
+		return nil, resp.ServiceError()
+	}
+	result := &ListUsersResult{}
+	if err := resp.ParseJSONBody(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func CreateRole(cli client.Client, args *CreateRoleArgs) error {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getRoleURI())
+	req.SetMethod(http.Post)
+	req.SetParam("create", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return err
+	}
+	if resp.IsFail() {
+		return resp.ServiceError()
+	}
+	defer func() { resp.Body().Close() }()
+	return nil
+}
+
+func DropRole(cli client.Client, role string) error {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getRoleURI())
+	req.SetMethod(http.Delete)
+	req.SetParam("role", role)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return err
+	}
+	if resp.IsFail() {
+		return resp.ServiceError()
+	}
+	defer func() { resp.Body().Close() }()
+	return nil
+}
+
+func ListRoles(cli client.Client) (*ListRolesResult, error) {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getRoleURI())
+	req.SetMethod(http.Post)
+	req.SetParam("list", "")
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.IsFail() {
+		return nil, resp.ServiceError()
+	}
+	result := &ListRolesResult{}
+	if err := resp.ParseJSONBody(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func GrantRole(cli client.Client, args *GrantRoleArgs) error {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getUserURI())
+	req.SetMethod(http.Post)
+	req.SetParam("grantRoles", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return err
+	}
+	if resp.IsFail() {
+		return resp.ServiceError()
+	}
+	defer func() { resp.Body().Close() }()
+	return nil
+}
+
+func RevokeRole(cli client.Client, args *RevokeRoleArgs) error {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(getUserURI())
+	req.SetMethod(http.Post)
+	req.SetParam("revokeRoles", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return err
+	}
+	if resp.IsFail() {
+		return resp.ServiceError()
+	}
+	defer func() { resp.Body().Close() }()
+	return nil
+}
+
+func GrantPrivilege(cli client.Client, args *GrantPrivilegeArgs) error {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(privilegeURI(args.Role))
+	req.SetMethod(http.Post)
+	req.SetParam("grantPrivileges", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return err
+	}
+	if resp.IsFail() {
+		return resp.ServiceError()
+	}
+	defer func() { resp.Body().Close() }()
+	return nil
+}
+
+func RevokePrivilege(cli client.Client, args *RevokePrivilegeArgs) error {
+	req := client.AcquireRequest()
+	defer client.ReleaseRequest(req)
+	req.SetURI(privilegeURI(args.Role))
+	req.SetMethod(http.Post)
+	req.SetParam("revokePrivileges", "")
+
+	jsonBytes, err := client.MarshalJSON(cli, args)
+	if err != nil {
+		return err
+	}
+	body, err := client.NewBodyFromBytes(jsonBytes)
+	if err != nil {
+		return err
+	}
+	req.SetBody(body)
+
+	resp := client.AcquireResponse()
+	defer client.ReleaseResponse(resp)
+	if err := cli.SendRequest(req, resp); err != nil {
+		return err
+	}
+	if resp.IsFail() {
+		return resp.ServiceError()
+	}
+	defer func() { resp.Body().Close() }()
+	return nil
+}
+
+// privilegeURI - the privileges of the role are granted on the role URI, otherwise the user URI
+func privilegeURI(role string) string {
+	if len(role) != 0 {
+		return getRoleURI()
+	}
+	return getUserURI()
+}
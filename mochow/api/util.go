@@ -16,6 +16,12 @@
 
 package api
 
+import (
+	"reflect"
+
+	"github.com/baidu/mochow-sdk-go/client"
+)
+
 const (
 	URIPrefixV1 = "/v1"
 
@@ -23,6 +29,10 @@ const (
 	RequestTableURI    = "/table"
 	RequestIndexURI    = "/index"
 	RequestRowURI      = "/row"
+	RequestUserURI     = "/user"
+	RequestRoleURI     = "/role"
+	RequestClusterURI  = "/cluster"
+	RequestTaskURI     = "/task"
 )
 
 func getDatabaseURI() string {
@@ -40,3 +50,38 @@ func getIndexURI() string {
 func getRowURI() string {
 	return URIPrefixV1 + RequestRowURI
 }
+
+func getUserURI() string {
+	return URIPrefixV1 + RequestUserURI
+}
+
+func getRoleURI() string {
+	return URIPrefixV1 + RequestRoleURI
+}
+
+func getClusterURI() string {
+	return URIPrefixV1 + RequestClusterURI
+}
+
+func getTaskURI() string {
+	return URIPrefixV1 + RequestTaskURI
+}
+
+// Pretouch compiles the json codec of the hot request and response models in advance, so that
+// the first requests of the ingestion and search workloads do not pay the compiling latency.
+func Pretouch() error {
+	return client.Pretouch(
+		reflect.TypeOf(InsertRowArgs{}),
+		reflect.TypeOf(UpsertRowArg{}),
+		reflect.TypeOf(QueryRowArgs{}),
+		reflect.TypeOf(QueryRowResult{}),
+		reflect.TypeOf(BatchQueryRowArgs{}),
+		reflect.TypeOf(BatchQueryRowResult{}),
+		reflect.TypeOf(SearchRowArgs{}),
+		reflect.TypeOf(SearchRowResult{}),
+		reflect.TypeOf(BatchSearchRowArgs{}),
+		reflect.TypeOf(BatchSearchRowResult{}),
+		reflect.TypeOf(SelectRowArgs{}),
+		reflect.TypeOf(SelectRowResult{}),
+	)
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// version.go - the version of the server to gate the features
+
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ServerVersion is the parsed version of the server, e.g. "2.1.3" or "v2.1.3-rc1"
+type ServerVersion struct {
+	Major int
+	Minor int
+	Patch int
+	// PreRelease is the suffix after '-', e.g. "rc1"
+	PreRelease string
+	Raw        string
+}
+
+// ParseServerVersion - parse the version of the server, the missing minor and patch are zero
+//
+// PARAMS:
+//   - version: the version string, the leading 'v' is optional
+//
+// RETURNS:
+//   - *ServerVersion: the parsed version
+//   - error: nil if ok otherwise the invalid version
+func ParseServerVersion(version string) (*ServerVersion, error) {
+	v := &ServerVersion{Raw: version}
+	s := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		s, v.PreRelease = s[:i], s[i+1:]
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return nil, fmt.Errorf("invalid server version %q", version)
+	}
+	numbers := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid server version %q", version)
+		}
+		*numbers[i] = n
+	}
+	return v, nil
+}
+
+// Compare returns -1, 0 or 1 if the version is older than, equal to or newer than the other, a
+// pre-release is older than its release
+func (v *ServerVersion) Compare(other *ServerVersion) int {
+	for _, d := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if d != 0 {
+			if d < 0 {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case v.PreRelease == other.PreRelease:
+		return 0
+	case len(v.PreRelease) == 0:
+		return 1
+	case len(other.PreRelease) == 0:
+		return -1
+	case v.PreRelease < other.PreRelease:
+		return -1
+	}
+	return 1
+}
+
+// AtLeast returns true if the version is not older than major.minor.patch, e.g. to check that the
+// server supports a feature
+func (v *ServerVersion) AtLeast(major, minor, patch int) bool {
+	return v.Compare(&ServerVersion{Major: major, Minor: minor, Patch: patch}) >= 0
+}
+
+func (v *ServerVersion) String() string {
+	return v.Raw
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// batch_search.go - the helper to fan out a large batch search into concurrent requests
+
+package mochow
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+const (
+	DefaultBatchSearchSize        = 100
+	DefaultBatchSearchConcurrency = 4
+)
+
+// ParallelBatchSearchRow - split the query vectors of the batch search into multiple BatchSearchRow
+// requests with at most batchSize vectors each, execute them with at most concurrency workers and
+// merge the results in the order of the query vectors.
+//
+// PARAMS:
+//   - args: the batch search args, the vectors may exceed the size limit of one request
+//   - batchSize: the max number of vectors in one request, use DefaultBatchSearchSize if not positive
+//   - concurrency: the number of workers, use DefaultBatchSearchConcurrency if not positive
+//
+// RETURNS:
+//   - *api.BatchSearchRowResult: the merged result with one SearchRowResult per query vector
+//   - error: the first error of the requests if any, or the error of a request returning not one
+//     result per vector
+func (c *Client) ParallelBatchSearchRow(args *api.BatchSearchRowArgs,
+	batchSize, concurrency int) (*api.BatchSearchRowResult, error) {
+	if args == nil || args.ANNS == nil {
+		return nil, errors.New("batch search args and anns should not be nil")
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSearchSize
+	}
+	if concurrency <= 0 {
+		concurrency = DefaultBatchSearchConcurrency
+	}
+
+	vectors := args.ANNS.VectorFloats
+	batches := (len(vectors) + batchSize - 1) / batchSize
+	if batches <= 1 {
+		return c.BatchSearchRow(args)
+	}
+	if concurrency > batches {
+		concurrency = batches
+	}
+
+	results := make([][]api.SearchRowResult, batches)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	failed := make(chan struct{})
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				end := (idx + 1) * batchSize
+				if end > len(vectors) {
+					end = len(vectors)
+				}
+				batchArgs := *args
+				anns := *args.ANNS
+				anns.VectorFloats = vectors[idx*batchSize : end]
+				batchArgs.ANNS = &anns
+
+				result, err := c.BatchSearchRow(&batchArgs)
+				if err == nil && len(result.Results) != end-idx*batchSize {
+					// the later results would be matched to the wrong vectors
+					err = fmt.Errorf("batch search of vectors [%d, %d) returns %d results",
+						idx*batchSize, end, len(result.Results))
+				}
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						close(failed)
+					})
+					continue
+				}
+				results[idx] = result.Results
+			}
+		}()
+	}
+
+dispatch:
+	for idx := 0; idx < batches; idx++ {
+		select {
+		case jobs <- idx:
+		case <-failed:
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	merged := &api.BatchSearchRowResult{Results: make([]api.SearchRowResult, 0, len(vectors))}
+	for _, result := range results {
+		merged.Results = append(merged.Results, result...)
+	}
+	return merged, nil
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+package mochow
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+// newBatchSearchServer - the server returning one result per query vector, except for the batch
+// starting with the vector short, which gets one result less
+func newBatchSearchServer(t *testing.T, short float32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var args struct {
+			ANNS struct {
+				VectorFloats [][]float32 `json:"vectorFloats"`
+			} `json:"anns"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+			t.Errorf("decode batch search args: %v", err)
+		}
+		results := make([]api.SearchRowResult, 0, len(args.ANNS.VectorFloats))
+		for _, vector := range args.ANNS.VectorFloats {
+			results = append(results, api.SearchRowResult{SearchVectorFloats: vector})
+		}
+		if len(args.ANNS.VectorFloats) != 0 && args.ANNS.VectorFloats[0][0] == short {
+			results = results[:len(results)-1]
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+	}))
+}
+
+func newBatchSearchArgs(vectors int) *api.BatchSearchRowArgs {
+	args := &api.BatchSearchRowArgs{
+		Database: "db",
+		Table:    "table",
+		ANNS:     &api.BatchANNSearchParams{VectorField: "vector"},
+	}
+	for i := 0; i < vectors; i++ {
+		args.ANNS.VectorFloats = append(args.ANNS.VectorFloats, []float32{float32(i)})
+	}
+	return args
+}
+
+func TestParallelBatchSearchRowOrder(t *testing.T) {
+	server := newBatchSearchServer(t, -1)
+	defer server.Close()
+	cli, err := NewClient("root", "key", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := cli.ParallelBatchSearchRow(newBatchSearchArgs(10), 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Results) != 10 {
+		t.Fatalf("got %d results, want 10", len(result.Results))
+	}
+	for i, r := range result.Results {
+		if r.SearchVectorFloats[0] != float32(i) {
+			t.Errorf("result %d is of vector %v", i, r.SearchVectorFloats)
+		}
+	}
+}
+
+func TestParallelBatchSearchRowMissingResult(t *testing.T) {
+	server := newBatchSearchServer(t, 3)
+	defer server.Close()
+	cli, err := NewClient("root", "key", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = cli.ParallelBatchSearchRow(newBatchSearchArgs(10), 3, 2)
+	if err == nil || !strings.Contains(err.Error(), "[3, 6) returns 2 results") {
+		t.Fatalf("got error %v, want the result count mismatch of the second batch", err)
+	}
+}
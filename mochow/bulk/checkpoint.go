@@ -0,0 +1,177 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// checkpoint.go - the progress checkpoints to resume the imports
+
+package bulk
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+// Checkpoint is the progress of an import, all the rows before it have been written
+type Checkpoint struct {
+	// Offset is the byte offset of the source for the imports resumed by seeking, zero if the import
+	// is resumed by Rows, e.g. ImportFile
+	Offset int64 `json:"offset"`
+	// LastPrimaryKey is the primary key of the last written row, e.g. for copying tables
+	LastPrimaryKey map[string]interface{} `json:"lastPrimaryKey,omitempty"`
+	// Rows is the number of rows written
+	Rows      int64     `json:"rows"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// CheckpointStore persists the checkpoints of the imports by the import id. Load returns nil
+// checkpoint without error if the import has no checkpoint.
+type CheckpointStore interface {
+	Load(id string) (*Checkpoint, error)
+	Save(id string, checkpoint *Checkpoint) error
+	Delete(id string) error
+}
+
+// MemoryCheckpointStore keeps the checkpoints in memory, it is mainly used for tests
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]Checkpoint
+}
+
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{checkpoints: make(map[string]Checkpoint)}
+}
+
+func (s *MemoryCheckpointStore) Load(id string) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	checkpoint, ok := s.checkpoints[id]
+	if !ok {
+		return nil, nil
+	}
+	return &checkpoint, nil
+}
+
+func (s *MemoryCheckpointStore) Save(id string, checkpoint *Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[id] = *checkpoint
+	return nil
+}
+
+func (s *MemoryCheckpointStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.checkpoints, id)
+	return nil
+}
+
+// FileCheckpointStore keeps each checkpoint in a json file named by the import id under Dir, the
+// file is replaced atomically so that a crash never leaves a partial checkpoint.
+type FileCheckpointStore struct {
+	Dir string
+}
+
+func (s *FileCheckpointStore) path(id string) (string, error) {
+	if len(id) == 0 || strings.ContainsAny(id, `/\`) || id == "." || id == ".." {
+		return "", errors.New("invalid checkpoint id: " + id)
+	}
+	return filepath.Join(s.Dir, id+".checkpoint"), nil
+}
+
+func (s *FileCheckpointStore) Load(id string) (*Checkpoint, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	checkpoint := &Checkpoint{}
+	if err := sonic.Unmarshal(data, checkpoint); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+func (s *FileCheckpointStore) Save(id string, checkpoint *Checkpoint) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	data, err := sonic.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.Dir, id+".checkpoint.*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *FileCheckpointStore) Delete(id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Checkpoint - flush the writer and save the checkpoint once all the rows added before have been
+// written, the checkpoint is not saved if any batch fails.
+//
+// PARAMS:
+//   - store: the store to persist the checkpoint
+//   - id: the id of the import
+//   - checkpoint: the progress of the rows added before
+//
+// RETURNS:
+//   - error: the error of the batches or the store if any
+func (w *BufferedWriter) Checkpoint(store CheckpointStore, id string, checkpoint *Checkpoint) error {
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	saved := *checkpoint
+	if saved.UpdatedAt.IsZero() {
+		saved.UpdatedAt = time.Now()
+	}
+	return store.Save(id, &saved)
+}
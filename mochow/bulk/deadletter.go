@@ -0,0 +1,160 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// deadletter.go - the sinks capturing the rows failed to be written
+
+package bulk
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+// FailedRow is the row failed to be written after the retries of the client
+type FailedRow struct {
+	Row api.Row
+	// Code is the server error code, it is zero if the error is not returned by the server
+	Code api.ServerErrCode
+	Err  error
+}
+
+// DeadLetterSink captures the failed rows so that the ingestion can continue and the rows can be
+// reprocessed later. Capture is called in the background goroutines of the writer.
+type DeadLetterSink interface {
+	Capture(rows []FailedRow) error
+}
+
+// DeadLetterFunc adapts a function to the DeadLetterSink
+type DeadLetterFunc func(rows []FailedRow) error
+
+func (f DeadLetterFunc) Capture(rows []FailedRow) error {
+	return f(rows)
+}
+
+// DeadLetterChan sends the failed rows to the channel, it blocks when the channel is full
+type DeadLetterChan chan<- FailedRow
+
+func (c DeadLetterChan) Capture(rows []FailedRow) error {
+	for _, row := range rows {
+		c <- row
+	}
+	return nil
+}
+
+// FileDeadLetter appends the failed rows to a file in json lines, which can be read back by
+// ReadDeadLetterFile. It is safe to be called by multiple goroutines.
+type FileDeadLetter struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+type deadLetterRecord struct {
+	Code  api.ServerErrCode `json:"code"`
+	Error string            `json:"error"`
+	Row   *api.Row          `json:"row"`
+}
+
+// NewFileDeadLetter - open the file to append the failed rows, the file is created if not exists
+//
+// PARAMS:
+//   - path: the path of the file
+//
+// RETURNS:
+//   - *FileDeadLetter: the sink which should be closed after used
+//   - error: nil if ok otherwise the specific error
+func NewFileDeadLetter(path string) (*FileDeadLetter, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileDeadLetter{file: file}, nil
+}
+
+func (f *FileDeadLetter) Capture(rows []FailedRow) error {
+	var buf []byte
+	for i := range rows {
+		record := deadLetterRecord{Code: rows[i].Code, Row: &rows[i].Row}
+		if rows[i].Err != nil {
+			record.Error = rows[i].Err.Error()
+		}
+		data, err := sonic.Marshal(&record)
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, data...), '\n')
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, err := f.file.Write(buf)
+	return err
+}
+
+// Close - close the file
+func (f *FileDeadLetter) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
+
+// ReadDeadLetterFile - read the failed rows written by FileDeadLetter
+//
+// PARAMS:
+//   - path: the path of the file
+//
+// RETURNS:
+//   - []FailedRow: the failed rows, the Err only keeps the error message
+//   - error: nil if ok otherwise the specific error
+func ReadDeadLetterFile(path string) ([]FailedRow, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var rows []FailedRow
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), DefaultMaxBatchBytes)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		record := deadLetterRecord{Row: &api.Row{}}
+		if err := sonic.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return rows, err
+		}
+		failed := FailedRow{Row: *record.Row, Code: record.Code}
+		if len(record.Error) != 0 {
+			failed.Err = errors.New(record.Error)
+		}
+		rows = append(rows, failed)
+	}
+	return rows, scanner.Err()
+}
+
+// newFailedRows - attach the error and its server error code to the rows of the failed batch
+func newFailedRows(rows []api.Row, err error) []FailedRow {
+	code, _ := api.ErrorCode(err)
+	failed := make([]FailedRow, len(rows))
+	for i, row := range rows {
+		failed[i] = FailedRow{Row: row, Code: code, Err: err}
+	}
+	return failed
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// dedup.go - the client side deduplication of the primary keys in a batch
+
+package bulk
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+// DedupPolicy defines how the rows with the same primary key in a batch are handled, the server
+// rejects the whole batch with PrimaryKeyDuplicated otherwise.
+type DedupPolicy int
+
+const (
+	// DedupNone sends the rows as is
+	DedupNone DedupPolicy = iota
+	// DedupKeepLast keeps the last row of the duplicated primary key
+	DedupKeepLast
+	// DedupError fails the batch with DuplicatePrimaryKeyError
+	DedupError
+)
+
+// DuplicatePrimaryKeyError reports the first duplicated primary key in a batch
+type DuplicatePrimaryKeyError struct {
+	PrimaryKey map[string]interface{}
+	// Indexes are the indexes of the first and the second row with the primary key
+	Indexes [2]int
+}
+
+func (e *DuplicatePrimaryKeyError) Error() string {
+	return fmt.Sprintf("duplicate primary key %v in the batch: rows %d and %d",
+		e.PrimaryKey, e.Indexes[0], e.Indexes[1])
+}
+
+// DedupRows - detect the rows with the same primary key in the batch
+//
+// PARAMS:
+//   - rows: the rows of the batch
+//   - primaryKey: the field names of the primary key
+//   - policy: how the duplicated rows are handled
+//
+// RETURNS:
+//   - []api.Row: the rows without duplicates in the original order, it is the input rows if there
+//     is no duplicate
+//   - error: *DuplicatePrimaryKeyError for DedupError or the missing primary key error
+func DedupRows(rows []api.Row, primaryKey []string, policy DedupPolicy) ([]api.Row, error) {
+	if policy == DedupNone || len(primaryKey) == 0 || len(rows) < 2 {
+		return rows, nil
+	}
+	last := make(map[string]int, len(rows))
+	keys := make([]string, len(rows))
+	duplicated := false
+	for i, row := range rows {
+		key, err := primaryKeyString(row, primaryKey)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i, err)
+		}
+		keys[i] = key
+		if j, ok := last[key]; ok {
+			if policy == DedupError {
+				pk := make(map[string]interface{}, len(primaryKey))
+				for _, name := range primaryKey {
+					pk[name] = row.Fields[name]
+				}
+				return nil, &DuplicatePrimaryKeyError{PrimaryKey: pk, Indexes: [2]int{j, i}}
+			}
+			duplicated = true
+		}
+		last[key] = i
+	}
+	if !duplicated {
+		return rows, nil
+	}
+	deduped := make([]api.Row, 0, len(last))
+	for i, row := range rows {
+		if last[keys[i]] == i {
+			deduped = append(deduped, row)
+		}
+	}
+	return deduped, nil
+}
+
+func primaryKeyString(row api.Row, primaryKey []string) (string, error) {
+	var b strings.Builder
+	for _, name := range primaryKey {
+		value, ok := row.Fields[name]
+		if !ok {
+			return "", fmt.Errorf("primary key field %s not found", name)
+		}
+		fmt.Fprintf(&b, "%v\x00", value)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// ratelimit.go - the rate limiter to throttle the writes to a table
+
+package bulk
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter limits the rows and bytes written per second with token buckets, the burst is one
+// second of the rate. A batch larger than the burst is allowed and delays the following batches.
+// One RateLimiter can be shared by the writers of the same table to cap their total rate.
+type RateLimiter struct {
+	mu    sync.Mutex
+	rows  tokenBucket
+	bytes tokenBucket
+}
+
+type tokenBucket struct {
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter - create the rate limiter, the rate is not limited if it is not positive
+//
+// PARAMS:
+//   - rowsPerSecond: the max number of rows written per second
+//   - bytesPerSecond: the max estimated bytes of rows written per second
+//
+// RETURNS:
+//   - *RateLimiter: the rate limiter
+func NewRateLimiter(rowsPerSecond, bytesPerSecond float64) *RateLimiter {
+	now := time.Now()
+	return &RateLimiter{
+		rows:  tokenBucket{rate: rowsPerSecond, tokens: rowsPerSecond, last: now},
+		bytes: tokenBucket{rate: bytesPerSecond, tokens: bytesPerSecond, last: now},
+	}
+}
+
+// reserve - take n tokens and return the time to wait until the tokens are available
+func (b *tokenBucket) reserve(n float64, now time.Time) time.Duration {
+	if b.rate <= 0 {
+		return 0
+	}
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.rate {
+		b.tokens = b.rate
+	}
+	b.last = now
+	b.tokens -= n
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// Reserve - take the quota of the rows and bytes and return the time to wait before writing
+func (l *RateLimiter) Reserve(rows, bytes int) time.Duration {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delay := l.rows.reserve(float64(rows), now)
+	if d := l.bytes.reserve(float64(bytes), now); d > delay {
+		delay = d
+	}
+	return delay
+}
+
+// Wait - block until the rows and bytes can be written
+func (l *RateLimiter) Wait(rows, bytes int) {
+	if delay := l.Reserve(rows, bytes); delay > 0 {
+		time.Sleep(delay)
+	}
+}
@@ -0,0 +1,420 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// writer.go - the asynchronous buffered writer of rows
+
+// Package bulk implements the bulk ingestion helpers of the Mochow service. The BufferedWriter,
+// a.k.a. the BulkWriter, accepts individual rows and upserts them in batches in the background
+// goroutines.
+package bulk
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/baidu/mochow-sdk-go/client"
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+const (
+	DefaultMaxBatchRows  = 1000
+	DefaultMaxBatchBytes = 4 << 20
+	DefaultFlushInterval = time.Second
+
+	DefaultMaxInFlightBatches = 1
+	DefaultMaxQueuedBatches   = 1
+
+	DefaultBatchRetryInterval = time.Second
+)
+
+// ErrWriterClosed is returned when adding rows to a closed writer
+var ErrWriterClosed = errors.New("bulk writer is closed")
+
+// Upserter is the subset of the Mochow client used by the writer, it is implemented by
+// *mochow.Client, e.g. cli.WithPriority(mochow.PriorityLow) for the backfills.
+type Upserter interface {
+	UpsertRow(args *api.UpsertRowArg) (*api.UpsertRowResult, error)
+}
+
+// WriterOptions defines the options of the BufferedWriter
+type WriterOptions struct {
+	Database string
+	Table    string
+	// MaxBatchRows flushes the buffer once the number of rows is reached, use
+	// DefaultMaxBatchRows if not positive
+	MaxBatchRows int
+	// MaxBatchBytes flushes the buffer once the estimated size of rows is reached, use
+	// DefaultMaxBatchBytes if not positive
+	MaxBatchBytes int
+	// FlushInterval flushes the buffer periodically, use DefaultFlushInterval if not positive
+	FlushInterval time.Duration
+	// MaxInFlightBatches is the max number of batches being written concurrently, the batches may
+	// be applied out of order if it is greater than 1, use DefaultMaxInFlightBatches if not positive
+	MaxInFlightBatches int
+	// MaxQueuedBatches is the max number of batches waiting to be written, Add blocks when the queue
+	// is full so that the producers are throttled, use DefaultMaxQueuedBatches if not positive
+	MaxQueuedBatches int
+	// MaxBatchRetries is the max number of retries of a failed batch for the retryable and the
+	// throttled errors besides the retries of the client, no retry if not positive
+	MaxBatchRetries int
+	// BatchRetryInterval is the interval before the first retry of a batch and doubles after each
+	// retry, use DefaultBatchRetryInterval if not positive
+	BatchRetryInterval time.Duration
+	// OnError is called in the background goroutine with the rows of the failed batch
+	OnError func(rows []api.Row, err error)
+	// OnProgress is called in the background goroutine after each batch is written or failed
+	OnProgress func(progress Progress)
+	// DeadLetter captures the rows of the failed batches, the errors of the batches captured
+	// successfully are not returned by Flush and Close so that the ingestion can continue
+	DeadLetter DeadLetterSink
+	// PrimaryKey is the field names of the primary key, it is required by Dedup
+	PrimaryKey []string
+	// Dedup defines how the rows with the same primary key in a batch are handled
+	Dedup DedupPolicy
+	// RowsPerSecond and BytesPerSecond cap the write rate of the writer so that the backfills do
+	// not starve the online traffic, the rate is not limited if it is not positive
+	RowsPerSecond  float64
+	BytesPerSecond float64
+	// RateLimiter overrides RowsPerSecond and BytesPerSecond, it can be shared by the writers of
+	// the same table to cap their total rate
+	RateLimiter *RateLimiter
+}
+
+// Progress is the cumulative progress of the writer
+type Progress struct {
+	WrittenRows    int
+	FailedRows     int
+	WrittenBatches int
+	FailedBatches  int
+}
+
+// BufferedWriter buffers the rows added by Add and upserts them in batches in the background
+// goroutines, the batch is flushed by the row count, the byte size or the time interval. The
+// errors of the batches are reported by OnError as well as returned by Flush and Close. The rows
+// expire by their own TTLSeconds, see api.Row. It is safe to be called by multiple goroutines.
+type BufferedWriter struct {
+	cli     Upserter
+	options WriterOptions
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	rows     []api.Row
+	size     int
+	inflight int
+	closed   bool
+
+	inflightRows int
+	errs         []error
+	progress     Progress
+
+	senders    sync.WaitGroup
+	workers    sync.WaitGroup
+	batches    chan []api.Row
+	stop       chan struct{}
+	tickerDone chan struct{}
+}
+
+// BulkWriter is the BufferedWriter used for the bulk loads, the worker pool is sized by
+// MaxInFlightBatches and the failed batches are retried by MaxBatchRetries
+type BulkWriter = BufferedWriter
+
+// NewBulkWriter - create the bulk writer, it is the same as NewBufferedWriter
+func NewBulkWriter(cli Upserter, options *WriterOptions) (*BulkWriter, error) {
+	return NewBufferedWriter(cli, options)
+}
+
+// NewBufferedWriter - create the writer and start the background goroutines
+//
+// PARAMS:
+//   - cli: the client to upsert rows, e.g. *mochow.Client
+//   - options: the options of the writer
+//
+// RETURNS:
+//   - *BufferedWriter: the writer which should be closed after used
+//   - error: nil if ok otherwise the specific error
+func NewBufferedWriter(cli Upserter, options *WriterOptions) (*BufferedWriter, error) {
+	if cli == nil || options == nil {
+		return nil, errors.New("client and options should not be nil")
+	}
+	if len(options.Database) == 0 || len(options.Table) == 0 {
+		return nil, errors.New("database and table should not be empty")
+	}
+	if options.Dedup != DedupNone && len(options.PrimaryKey) == 0 {
+		return nil, errors.New("primary key should not be empty when dedup is enabled")
+	}
+	w := &BufferedWriter{
+		cli:        cli,
+		options:    *options,
+		stop:       make(chan struct{}),
+		tickerDone: make(chan struct{}),
+	}
+	w.cond = sync.NewCond(&w.mu)
+	if w.options.MaxBatchRows <= 0 {
+		w.options.MaxBatchRows = DefaultMaxBatchRows
+	}
+	if w.options.MaxBatchBytes <= 0 {
+		w.options.MaxBatchBytes = DefaultMaxBatchBytes
+	}
+	if w.options.FlushInterval <= 0 {
+		w.options.FlushInterval = DefaultFlushInterval
+	}
+	if w.options.MaxInFlightBatches <= 0 {
+		w.options.MaxInFlightBatches = DefaultMaxInFlightBatches
+	}
+	if w.options.MaxQueuedBatches <= 0 {
+		w.options.MaxQueuedBatches = DefaultMaxQueuedBatches
+	}
+	if w.options.BatchRetryInterval <= 0 {
+		w.options.BatchRetryInterval = DefaultBatchRetryInterval
+	}
+
+	if w.options.RateLimiter == nil && (w.options.RowsPerSecond > 0 || w.options.BytesPerSecond > 0) {
+		w.options.RateLimiter = NewRateLimiter(w.options.RowsPerSecond, w.options.BytesPerSecond)
+	}
+
+	w.batches = make(chan []api.Row, w.options.MaxQueuedBatches)
+	w.workers.Add(w.options.MaxInFlightBatches)
+	for i := 0; i < w.options.MaxInFlightBatches; i++ {
+		go w.work()
+	}
+	go w.tick()
+	return w, nil
+}
+
+// Add - add the rows to the buffer, it blocks when a batch is full and the queue of the batches is
+// full, so that the producers are throttled to what the background goroutines can write.
+func (w *BufferedWriter) Add(rows ...api.Row) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrWriterClosed
+	}
+	var batches [][]api.Row
+	for _, row := range rows {
+		w.rows = append(w.rows, row)
+		w.size += EstimateRowSize(row)
+		if len(w.rows) >= w.options.MaxBatchRows || w.size >= w.options.MaxBatchBytes {
+			batches = append(batches, w.takeLocked())
+		}
+	}
+	w.senders.Add(len(batches))
+	w.mu.Unlock()
+
+	for _, batch := range batches {
+		w.batches <- batch
+		w.senders.Done()
+	}
+	return nil
+}
+
+// Pending returns the number of rows buffered, queued or being written
+func (w *BufferedWriter) Pending() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.rows) + w.inflightRows
+}
+
+// Progress returns the cumulative progress of the writer
+func (w *BufferedWriter) Progress() Progress {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.progress
+}
+
+// Flush - flush the buffered rows and wait for all the batches to be written
+//
+// RETURNS:
+//   - error: the error of the batches failed since the last Flush if any
+func (w *BufferedWriter) Flush() error {
+	w.flushBuffer()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for w.inflight > 0 {
+		w.cond.Wait()
+	}
+	errs := w.errs
+	w.errs = nil
+	return joinErrors(errs)
+}
+
+// Close - flush the buffered rows, wait for all the batches to be written and stop the background
+// goroutines, the writer can not be used after closed.
+//
+// RETURNS:
+//   - error: the error of the batches failed since the last Flush if any
+func (w *BufferedWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrWriterClosed
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	// stop the ticker before flushing so that no batch is sent after the channel is closed
+	close(w.stop)
+	<-w.tickerDone
+	err := w.Flush()
+	w.senders.Wait()
+	close(w.batches)
+	w.workers.Wait()
+	return err
+}
+
+// flushBuffer - hand off the buffered rows to the background goroutines
+func (w *BufferedWriter) flushBuffer() {
+	w.mu.Lock()
+	if len(w.rows) == 0 {
+		w.mu.Unlock()
+		return
+	}
+	batch := w.takeLocked()
+	w.senders.Add(1)
+	w.mu.Unlock()
+
+	w.batches <- batch
+	w.senders.Done()
+}
+
+// takeLocked - take the buffered rows as a batch, the mutex should be held
+func (w *BufferedWriter) takeLocked() []api.Row {
+	batch := w.rows
+	w.rows = nil
+	w.size = 0
+	w.inflight++
+	w.inflightRows += len(batch)
+	return batch
+}
+
+func (w *BufferedWriter) work() {
+	defer w.workers.Done()
+	for batch := range w.batches {
+		w.write(batch)
+	}
+}
+
+func (w *BufferedWriter) tick() {
+	defer close(w.tickerDone)
+	ticker := time.NewTicker(w.options.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.flushBuffer()
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+func (w *BufferedWriter) write(batch []api.Row) {
+	rows, err := DedupRows(batch, w.options.PrimaryKey, w.options.Dedup)
+	if err == nil {
+		if w.options.RateLimiter != nil {
+			size := 0
+			for _, row := range rows {
+				size += EstimateRowSize(row)
+			}
+			w.options.RateLimiter.Wait(len(rows), size)
+		}
+		err = w.upsert(rows)
+	}
+	failed := err != nil
+	if failed && w.options.OnError != nil {
+		w.options.OnError(batch, err)
+	}
+
+	if err != nil && w.options.DeadLetter != nil {
+		if captureErr := w.options.DeadLetter.Capture(newFailedRows(batch, err)); captureErr != nil {
+			err = fmt.Errorf("capture %d failed rows to dead letter failed: %v, write error: %w",
+				len(batch), captureErr, err)
+		} else {
+			err = nil
+		}
+	}
+
+	w.mu.Lock()
+	if failed {
+		w.progress.FailedRows += len(batch)
+		w.progress.FailedBatches++
+	} else {
+		w.progress.WrittenRows += len(batch)
+		w.progress.WrittenBatches++
+	}
+	progress := w.progress
+	if err != nil {
+		w.errs = append(w.errs, err)
+	}
+	w.inflight--
+	w.inflightRows -= len(batch)
+	w.cond.Broadcast()
+	w.mu.Unlock()
+
+	if w.options.OnProgress != nil {
+		w.options.OnProgress(progress)
+	}
+}
+
+// upsert - upsert the rows with the retries of the retryable and the throttled errors
+func (w *BufferedWriter) upsert(rows []api.Row) error {
+	interval := w.options.BatchRetryInterval
+	for retries := 0; ; retries++ {
+		_, err := w.cli.UpsertRow(&api.UpsertRowArg{
+			Database: w.options.Database,
+			Table:    w.options.Table,
+			Rows:     rows,
+		})
+		if err == nil || retries >= w.options.MaxBatchRetries ||
+			(!client.IsRetryable(err) && !client.IsThrottled(err)) {
+			return err
+		}
+		time.Sleep(interval)
+		interval *= 2
+	}
+}
+
+// EstimateRowSize - estimate the marshaled size of the row without marshaling it
+func EstimateRowSize(row api.Row) int {
+	size := 2
+	for key, value := range row.Fields {
+		size += len(key) + 4
+		switch v := value.(type) {
+		case string:
+			size += len(v) + 2
+		case []byte:
+			size += len(v)*4/3 + 2
+		case []float32:
+			size += len(v) * 12
+		case []float64:
+			size += len(v) * 20
+		default:
+			size += 20
+		}
+	}
+	return size
+}
+
+// joinErrors - combine the errors into one error which unwraps to the first error
+func joinErrors(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+	return fmt.Errorf("%d batches failed, the first error: %w", len(errs), errs[0])
+}
@@ -0,0 +1,258 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// call_options.go - the options applied to the requests of a derived client
+
+package mochow
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/baidu/mochow-sdk-go/auth"
+	"github.com/baidu/mochow-sdk-go/client"
+)
+
+const (
+	// TagsHeader is the header carrying the request tags, the tags are encoded as a url query,
+	// e.g. "job=backfill&team=search"
+	TagsHeader = "X-Mochow-Tags"
+	// PriorityHeader is the header carrying the priority hint of the request
+	PriorityHeader = "X-Mochow-Priority"
+	// ReadPreferenceHeader is the header carrying the replica routing preference of the read
+	// request
+	ReadPreferenceHeader = "X-Mochow-Read-Preference"
+)
+
+// Priority is the hint for the cluster to distinguish the latency critical requests from the batch
+// traffic, it takes effect only if the server honors it.
+type Priority string
+
+const (
+	PriorityHigh   Priority = "HIGH"
+	PriorityNormal Priority = "NORMAL"
+	PriorityLow    Priority = "LOW"
+)
+
+// ReadPreference steers the read requests among the replicas, e.g. the analytical scans can be
+// kept away from the write leaders, it takes effect only if the server supports it.
+type ReadPreference string
+
+const (
+	// ReadLeaderOnly reads from the leader replicas only
+	ReadLeaderOnly ReadPreference = "LEADER_ONLY"
+	// ReadNearest reads from the replica with the lowest latency
+	ReadNearest ReadPreference = "NEAREST"
+	// ReadReplicaOK prefers the follower replicas and falls back to the leaders
+	ReadReplicaOK ReadPreference = "REPLICA_OK"
+)
+
+// readParams are the params of the read requests of the row APIs
+var readParams = []string{
+	"query", "batchQuery", "search", "batchSearch", "hybridSearch", "multiVectorSearch",
+	"rangeSearch", "select", "aggregate",
+}
+
+// callOptions are applied to each request sent by the client, they are set on a copy of the
+// client returned by the With methods so that the original client is not affected.
+type callOptions struct {
+	ctx         context.Context
+	rawResponse *client.RawResponse
+	metadata    *client.ResponseMetadata
+	tags        map[string]string
+	headers     map[string]string
+	priority    Priority
+	readPref    ReadPreference
+	credentials *auth.BceCredentials
+}
+
+// WithContext - derive a client whose requests are canceled or time-bounded by the ctx, including
+// the waiting before the retries, and the trace headers and response metadata carried by the ctx
+// are applied as WithTrace does. Its UpsertRow calls bypass the write pipeline.
+//
+// PARAMS:
+//   - ctx: the context of the requests
+//
+// RETURNS:
+//   - *Client: the derived client sharing the connections and the caches with c
+func (c *Client) WithContext(ctx context.Context) *Client {
+	derived := *c.WithTrace(ctx)
+	derived.callOptions.ctx = ctx
+	return &derived
+}
+
+// WithRawResponse - derive a client which fills the raw response of its requests, so that the
+// server fields not modeled by the SDK can be accessed besides the parsed result. The derived
+// client is meant for one call at a time since the raw response is overwritten by each request,
+// and the cached QueryRow results do not fill it. Its UpsertRow calls bypass the write pipeline.
+//
+// PARAMS:
+//   - raw: the raw response to fill, the Body is filled when the body is parsed or the request
+//     fails
+//
+// RETURNS:
+//   - *Client: the derived client sharing the connections and the caches with c
+func (c *Client) WithRawResponse(raw *client.RawResponse) *Client {
+	derived := *c
+	derived.callOptions.rawResponse = raw
+	return &derived
+}
+
+// WithResponseMetadata - derive a client which fills the request id and debug id of its requests,
+// the request id of a failed request is also kept by the returned *client.BceServiceError. The
+// derived client is meant for one call at a time since the metadata is overwritten by each request.
+//
+// PARAMS:
+//   - metadata: the metadata to fill
+//
+// RETURNS:
+//   - *Client: the derived client sharing the connections and the caches with c
+func (c *Client) WithResponseMetadata(metadata *client.ResponseMetadata) *Client {
+	derived := *c
+	derived.callOptions.metadata = metadata
+	return &derived
+}
+
+// WithTags - derive a client which attaches the tags to its requests besides the tags of the client
+// configuration, e.g. the team, job or tenant, so that the server side access logs and billing
+// can attribute the traffic. The tags with the same keys override the configured ones.
+//
+// PARAMS:
+//   - tags: the tags to attach
+//
+// RETURNS:
+//   - *Client: the derived client sharing the connections and the caches with c
+func (c *Client) WithTags(tags map[string]string) *Client {
+	derived := *c
+	merged := make(map[string]string, len(c.callOptions.tags)+len(tags))
+	for k, v := range c.callOptions.tags {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	derived.callOptions.tags = merged
+	return &derived
+}
+
+// WithHeaders - derive a client which adds the headers to its requests, e.g. the tracing baggage
+// or the canary flags. The headers override the ExtraHeaders of the client configuration and are
+// set before signing.
+//
+// PARAMS:
+//   - headers: the headers to add
+//
+// RETURNS:
+//   - *Client: the derived client sharing the connections and the caches with c
+func (c *Client) WithHeaders(headers map[string]string) *Client {
+	derived := *c
+	merged := make(map[string]string, len(c.callOptions.headers)+len(headers))
+	for k, v := range c.callOptions.headers {
+		merged[k] = v
+	}
+	for k, v := range headers {
+		merged[k] = v
+	}
+	derived.callOptions.headers = merged
+	return &derived
+}
+
+// WithPriority - derive a client which sends its requests with the priority hint, e.g. the
+// PriorityLow for the backfill writers and the PriorityHigh for the online searches
+//
+// PARAMS:
+//   - priority: the priority hint
+//
+// RETURNS:
+//   - *Client: the derived client sharing the connections and the caches with c
+func (c *Client) WithPriority(priority Priority) *Client {
+	derived := *c
+	derived.callOptions.priority = priority
+	return &derived
+}
+
+// WithReadPreference - derive a client which sends its read requests, i.e. the query, search,
+// select and aggregate of the rows, with the replica routing preference
+//
+// PARAMS:
+//   - preference: the read preference
+//
+// RETURNS:
+//   - *Client: the derived client sharing the connections and the caches with c
+func (c *Client) WithReadPreference(preference ReadPreference) *Client {
+	derived := *c
+	derived.callOptions.readPref = preference
+	return &derived
+}
+
+func isReadRequest(req *client.BceRequest) bool {
+	params := req.Params()
+	for _, param := range readParams {
+		if _, ok := params[param]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func encodeTags(tags map[string]string) string {
+	values := make(url.Values, len(tags))
+	for k, v := range tags {
+		values.Set(k, v)
+	}
+	return values.Encode()
+}
+
+func (c *Client) applyCallOptions(req *client.BceRequest, resp *client.BceResponse) {
+	if c.callOptions.ctx != nil {
+		req.SetContext(c.callOptions.ctx)
+	}
+	for key, value := range c.callOptions.headers {
+		req.SetHeader(key, value)
+	}
+	if len(c.callOptions.priority) != 0 {
+		req.SetHeader(PriorityHeader, string(c.callOptions.priority))
+	}
+	if len(c.callOptions.tags) != 0 {
+		req.SetHeader(TagsHeader, encodeTags(c.callOptions.tags))
+	}
+	if len(c.callOptions.readPref) != 0 && isReadRequest(req) {
+		req.SetHeader(ReadPreferenceHeader, string(c.callOptions.readPref))
+	}
+	if c.callOptions.metadata != nil {
+		resp.SetResponseMetadata(c.callOptions.metadata)
+	}
+	if c.callOptions.rawResponse != nil {
+		resp.SetRawResponse(c.callOptions.rawResponse)
+	}
+}
+
+// SendRequest - apply the call options and send the request by the BceClient
+func (c *Client) SendRequest(req *client.BceRequest, resp *client.BceResponse) error {
+	c.applyCallOptions(req, resp)
+	if err := c.applyCredentials(req, req.Content()); err != nil {
+		return err
+	}
+	return c.BceClient.SendRequest(req, resp)
+}
+
+// SendRequestFromBytes - apply the call options and send the request by the BceClient
+func (c *Client) SendRequestFromBytes(req *client.BceRequest, resp *client.BceResponse,
+	content []byte) error {
+	c.applyCallOptions(req, resp)
+	if err := c.applyCredentials(req, content); err != nil {
+		return err
+	}
+	return c.BceClient.SendRequestFromBytes(req, resp, content)
+}
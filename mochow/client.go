@@ -17,7 +17,10 @@
 package mochow
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"sync"
 
 	"github.com/baidu/mochow-sdk-go/auth"
 	"github.com/baidu/mochow-sdk-go/client"
@@ -26,6 +29,18 @@ import (
 
 type Client struct {
 	*client.BceClient
+
+	pipeline    *writePipeline
+	queryCache  *queryCache
+	aliases     *aliasResolver
+	schemaCache *schemaCache
+
+	credentialProvider CredentialProvider
+
+	disableNameValidation bool
+	disableArgsValidation bool
+	validateFilters       bool
+	callOptions           callOptions
 }
 
 type ClientConfiguration struct {
@@ -33,9 +48,70 @@ type ClientConfiguration struct {
 	APIKey              string
 	Endpoint            string
 	RedirectDisabled    bool
+	MaxRedirects        int
 	ConnectionTimeoutMS int
 	RequestTimeoutMS    int
 	MaxRetry            int
+	// KeepAliveMS is the TCP keep-alive period of the connections, use the default if zero and
+	// disable the keep-alive probes if negative
+	KeepAliveMS int
+	// SmallIntervalMS and LargeIntervalMS are the read and write deadlines of the ongoing io
+	// operations and the idle connections, use the defaults if not positive
+	//
+	// The connections are shared by all the clients of the process, so the ConnectionTimeoutMS,
+	// KeepAliveMS, SmallIntervalMS and LargeIntervalMS of the first created client take effect
+	// and those of the later clients are ignored.
+	SmallIntervalMS int
+	LargeIntervalMS int
+	// JSONOptions tunes the json codec for the ingestion heavy workloads, e.g. disable sorting
+	// map keys and pretouch the hot models when creating the client
+	JSONOptions *client.JSONOptions
+	// WritePipeline enables coalescing the small UpsertRow calls to the same table into one
+	// request transparently, nil means disabled
+	WritePipeline *WritePipelineOptions
+	// QueryCache enables the client side LRU cache of the QueryRow results for the hot keys,
+	// nil means disabled
+	QueryCache *QueryCacheOptions
+	// Aliases enables caching the resolved aliases and resolving the table names passed to the
+	// Client methods as aliases, nil means disabled
+	Aliases *AliasOptions
+	// SchemaCache enables caching the table schemas to check the rows of InsertRow and UpsertRow
+	// against them before sending, nil means disabled
+	SchemaCache *SchemaCacheOptions
+	// CredentialProvider selects the credentials of the requests by their databases, the Account
+	// and APIKey are used if it is nil or returns nil, the credentials of a call can be set by
+	// Client.WithCredentials
+	CredentialProvider CredentialProvider
+	// DisableNameValidation skips checking the names of the created databases, tables, aliases,
+	// fields and indexes before sending the requests
+	DisableNameValidation bool
+	// DisableArgsValidation skips checking the schemas of the created tables, the created indexes
+	// and the written rows before sending the requests, e.g. the primary keys and the dimensions
+	DisableArgsValidation bool
+	// ValidateFilters checks the syntax of the filters of the select, search, delete and
+	// aggregate requests before sending them, the errors are reported with the positions
+	ValidateFilters bool
+	// Tags are attached to all the requests in the TagsHeader, e.g. the team or job name, the
+	// tags of a call can be added by Client.WithTags
+	Tags map[string]string
+	// ExtraHeaders are added to all the requests, e.g. the gateway routing headers, the headers of
+	// a call can be added by Client.WithHeaders
+	ExtraHeaders map[string]string
+	// Priority is the default priority hint of the requests, the priority of a call can be set by
+	// Client.WithPriority, no hint if empty
+	Priority Priority
+	// ReadPreference is the default replica routing preference of the read requests, the
+	// preference of a call can be set by Client.WithReadPreference, no preference if empty
+	ReadPreference ReadPreference
+	// SendRequestMD5 sends the Content-MD5 headers of the request bodies
+	SendRequestMD5 bool
+	// VerifyResponseMD5 verifies the response bodies against their Content-MD5 headers
+	VerifyResponseMD5 bool
+	// CRC32CThresholdBytes adds the CRC32C checksum header to the request bodies not smaller than
+	// it, e.g. the large upserts, disabled if not positive
+	CRC32CThresholdBytes int64
+	// VerifyResponseCRC32C verifies the response bodies against their CRC32C checksum headers
+	VerifyResponseCRC32C bool
 }
 
 // NewClient make the Mochow service client with default configuration.
@@ -72,7 +148,16 @@ func NewClientWithConfig(config *ClientConfiguration) (*Client, error) {
 		Retry:                     client.DefaultRetryPolicy,
 		ConnectionTimeoutInMillis: client.DefaultConnectionTimeoutInMills,
 		RequestTimeoutInMillis:    client.DefaultRequestTimeoutInMills,
-		RedirectDisabled:          config.RedirectDisabled}
+		RedirectDisabled:          config.RedirectDisabled,
+		MaxRedirects:              config.MaxRedirects,
+		KeepAliveInMillis:         config.KeepAliveMS,
+		SmallIntervalInMillis:     config.SmallIntervalMS,
+		LargeIntervalInMillis:     config.LargeIntervalMS,
+		ExtraHeaders:              config.ExtraHeaders,
+		SendRequestMD5:            config.SendRequestMD5,
+		VerifyResponseMD5:         config.VerifyResponseMD5,
+		CRC32CThresholdInBytes:    config.CRC32CThresholdBytes,
+		VerifyResponseCRC32C:      config.VerifyResponseCRC32C}
 
 	// Check timeout options
 	if config.ConnectionTimeoutMS < 0 || config.RequestTimeoutMS < 0 {
@@ -96,18 +181,115 @@ func NewClientWithConfig(config *ClientConfiguration) (*Client, error) {
 		defaultConf.Retry = client.NewBackOffRetryPolicy(config.MaxRetry, 20000, 300)
 	}
 
+	// Check json codec options
+	if config.JSONOptions != nil {
+		defaultConf.JSONOptions = config.JSONOptions
+		if config.JSONOptions.Pretouch {
+			if err := api.Pretouch(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	v1Signer := &auth.BceV1Signer{}
-	client := &Client{client.NewBceClient(defaultConf, v1Signer)}
+	client := &Client{
+		BceClient:             client.NewBceClient(defaultConf, v1Signer),
+		credentialProvider:    config.CredentialProvider,
+		disableNameValidation: config.DisableNameValidation,
+		disableArgsValidation: config.DisableArgsValidation,
+		validateFilters:       config.ValidateFilters,
+	}
+	if len(config.Tags) != 0 {
+		client = client.WithTags(config.Tags)
+	}
+	if len(config.Priority) != 0 {
+		client = client.WithPriority(config.Priority)
+	}
+	if len(config.ReadPreference) != 0 {
+		client = client.WithReadPreference(config.ReadPreference)
+	}
+	if config.WritePipeline != nil {
+		client.pipeline = newWritePipeline(client, config.WritePipeline)
+	}
+	if config.QueryCache != nil {
+		client.queryCache = newQueryCache(config.QueryCache)
+	}
+	if config.Aliases != nil {
+		client.aliases = newAliasResolver(config.Aliases)
+	}
+	if config.SchemaCache != nil {
+		client.schemaCache = newSchemaCache(config.SchemaCache)
+	}
 	return client, nil
 }
 
+/********************* Cluster interfaces *********************/
+func (c *Client) DescCluster() (*api.DescClusterResult, error) {
+	return api.DescCluster(c)
+}
+
+func (c *Client) ListNodes() (*api.ListNodesResult, error) {
+	return api.ListNodes(c)
+}
+
+// ServerVersion - get the version of the cluster, e.g. to check that a feature is supported by
+// version.AtLeast(2, 1, 0)
+//
+// RETURNS:
+//   - *api.ServerVersion: the parsed version of the cluster
+//   - error: nil if ok otherwise the specific error
+func (c *Client) ServerVersion() (*api.ServerVersion, error) {
+	result, err := c.DescCluster()
+	if err != nil {
+		return nil, err
+	}
+	return api.ParseServerVersion(result.Version)
+}
+
+/********************* Task interfaces *********************/
+func (c *Client) DescTask(taskID string) (*api.Task, error) {
+	args := &api.DescTaskArgs{TaskID: taskID}
+	result, err := api.DescTask(c, args)
+	if err != nil {
+		return nil, err
+	}
+	if result.Task == nil {
+		return nil, fmt.Errorf("task %s not found", taskID)
+	}
+	return result.Task, nil
+}
+
+func (c *Client) ListTasks(args *api.ListTasksArgs) (*api.ListTasksResult, error) {
+	if args == nil {
+		args = &api.ListTasksArgs{}
+	}
+	return api.ListTasks(c, args)
+}
+
+func (c *Client) CancelTask(taskID string) error {
+	args := &api.CancelTaskArgs{TaskID: taskID}
+	return api.CancelTask(c, args)
+}
+
 /********************* Database interfaces *********************/
 func (c *Client) CreateDatabase(database string) error {
+	if !c.disableNameValidation {
+		if err := ValidateName("database", database); err != nil {
+			return err
+		}
+	}
 	args := &api.CreateDatabaseArgs{Database: database}
 	return api.CreateDatabase(c, args)
 }
 
 func (c *Client) DropDatabase(database string) error {
+	if c.queryCache != nil {
+		defer c.queryCache.invalidateDatabase(database)
+	}
+	if c.pipeline != nil {
+		defer c.pipeline.invalidateDatabase(database)
+	}
+	defer c.schemaCache.invalidateDatabase(database)
 	return api.DropDatabase(c, database)
 }
 
@@ -115,6 +297,35 @@ func (c *Client) ListDatabase() (*api.ListDatabaseResult, error) {
 	return api.ListDatabase(c)
 }
 
+// Warmup - pre-establish n connections(TCP and TLS handshakes) to the endpoint by n concurrent
+// ListDatabase requests, which are signed and sent like the other requests, so that the first
+// burst of requests does not pay the connection setup latency and the warm-up is neither rejected
+// by the authentication nor logged as a bad request by the server.
+//
+// PARAMS:
+//   - ctx: the context to cancel the warm-up
+//   - n: the number of connections
+//
+// RETURNS:
+//   - error: the first error of the requests if any
+func (c *Client) Warmup(ctx context.Context, n int) error {
+	cli := c.WithContext(ctx)
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cli.ListDatabase(); err != nil {
+				once.Do(func() { firstErr = err })
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
+
 func (c *Client) HasDatabase(database string) (bool, error) {
 	listDatabaseResult, err := c.ListDatabase()
 	if err != nil {
@@ -128,12 +339,180 @@ func (c *Client) HasDatabase(database string) (bool, error) {
 	return false, nil
 }
 
+func (c *Client) DescDatabase(database string) (*api.DescDatabaseResult, error) {
+	args := &api.DescDatabaseArgs{Database: database}
+	return api.DescDatabase(c, args)
+}
+
+// ShowDatabaseStats - sum up the stats of the tables of the database
+//
+// PARAMS:
+//   - database: the database name
+//
+// RETURNS:
+//   - *api.ShowDatabaseStatsResult: the total row count and sizes, and the stats of each table
+//   - error: nil if ok otherwise the specific error
+func (c *Client) ShowDatabaseStats(database string) (*api.ShowDatabaseStatsResult, error) {
+	tables, err := c.ListTable(database)
+	if err != nil {
+		return nil, err
+	}
+	result := &api.ShowDatabaseStatsResult{
+		Tables: make(map[string]*api.ShowTableStatsResult, len(tables.Tables)),
+	}
+	for _, table := range tables.Tables {
+		args := &api.ShowTableStatsArgs{Database: database, Table: table}
+		stats, err := api.ShowTableStats(c, args)
+		if api.IsTableNotExist(err) {
+			// dropped after listed
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("show stats of table %s failed: %w", table, err)
+		}
+		result.TableCount++
+		result.RowCount += stats.RowCount
+		result.MemorySizeInByte += stats.MemorySizeInByte
+		result.DiskSizeInByte += stats.DiskSizeInByte
+		result.Tables[table] = stats
+	}
+	return result, nil
+}
+
+/********************* User and role interfaces *********************/
+func (c *Client) CreateUser(username, password string) error {
+	if !c.disableNameValidation {
+		if err := ValidateName("user", username); err != nil {
+			return err
+		}
+	}
+	if len(password) == 0 {
+		return errors.New("password should not be empty")
+	}
+	args := &api.CreateUserArgs{Username: username, Password: password}
+	return api.CreateUser(c, args)
+}
+
+func (c *Client) DropUser(username string) error {
+	return api.DropUser(c, username)
+}
+
+func (c *Client) ChangePassword(username, password string) error {
+	if len(password) == 0 {
+		return errors.New("password should not be empty")
+	}
+	args := &api.ChangePasswordArgs{Username: username, Password: password}
+	return api.ChangePassword(c, args)
+}
+
+func (c *Client) ListUsers() (*api.ListUsersResult, error) {
+	return api.ListUsers(c)
+}
+
+func (c *Client) CreateRole(role string) error {
+	if !c.disableNameValidation {
+		if err := ValidateName("role", role); err != nil {
+			return err
+		}
+	}
+	args := &api.CreateRoleArgs{Role: role}
+	return api.CreateRole(c, args)
+}
+
+func (c *Client) DropRole(role string) error {
+	return api.DropRole(c, role)
+}
+
+func (c *Client) ListRoles() (*api.ListRolesResult, error) {
+	return api.ListRoles(c)
+}
+
+func (c *Client) GrantRole(username string, roles ...string) error {
+	if len(roles) == 0 {
+		return errors.New("roles should not be empty")
+	}
+	args := &api.GrantRoleArgs{Username: username, Roles: roles}
+	return api.GrantRole(c, args)
+}
+
+func (c *Client) RevokeRole(username string, roles ...string) error {
+	if len(roles) == 0 {
+		return errors.New("roles should not be empty")
+	}
+	args := &api.RevokeRoleArgs{Username: username, Roles: roles}
+	return api.RevokeRole(c, args)
+}
+
+// GrantPrivilege - grant the privileges on the databases and tables to the user or the role
+//
+// PARAMS:
+//   - args: the grant args, either Username or Role should be set
+//
+// RETURNS:
+//   - error: nil if ok otherwise the specific error
+func (c *Client) GrantPrivilege(args *api.GrantPrivilegeArgs) error {
+	if err := checkPrivilegeArgs(args.Username, args.Role, args.PrivilegeTuples); err != nil {
+		return err
+	}
+	return api.GrantPrivilege(c, args)
+}
+
+// RevokePrivilege - revoke the privileges on the databases and tables from the user or the role
+//
+// PARAMS:
+//   - args: the revoke args, either Username or Role should be set
+//
+// RETURNS:
+//   - error: nil if ok otherwise the specific error
+func (c *Client) RevokePrivilege(args *api.RevokePrivilegeArgs) error {
+	if err := checkPrivilegeArgs(args.Username, args.Role, args.PrivilegeTuples); err != nil {
+		return err
+	}
+	return api.RevokePrivilege(c, args)
+}
+
+func checkPrivilegeArgs(username, role string, tuples []api.PrivilegeTuple) error {
+	if (len(username) == 0) == (len(role) == 0) {
+		return errors.New("exactly one of username and role should be set")
+	}
+	if len(tuples) == 0 {
+		return errors.New("privilege tuples should not be empty")
+	}
+	for _, tuple := range tuples {
+		if len(tuple.Database) == 0 || len(tuple.Table) == 0 || len(tuple.Privileges) == 0 {
+			return errors.New("database, table and privileges of privilege tuple should not be empty")
+		}
+	}
+	return nil
+}
+
 /********************* Table interfaces *********************/
 func (c *Client) CreateTable(args *api.CreateTableArgs) error {
+	if !c.disableArgsValidation {
+		if err := checkTableArgs(args); err != nil {
+			return err
+		}
+	}
+	if !c.disableNameValidation {
+		if err := validateCreateTableArgs(args); err != nil {
+			return err
+		}
+	}
 	return api.CreateTable(c, args)
 }
 
 func (c *Client) DropTable(database, table string) error {
+	table, err := c.resolveTable(database, table)
+	if err != nil {
+		return err
+	}
+	if c.queryCache != nil {
+		defer c.queryCache.invalidateTable(database, table)
+	}
+	if c.pipeline != nil {
+		defer c.pipeline.invalidateTable(database, table)
+	}
+	defer c.schemaCache.invalidate(database, table)
 	return api.DropTable(c, database, table)
 }
 
@@ -156,79 +535,726 @@ func (c *Client) HasTable(database, table string) (bool, error) {
 }
 
 func (c *Client) DescTable(database, table string) (*api.DescTableResult, error) {
+	table, err := c.resolveTable(database, table)
+	if err != nil {
+		return nil, err
+	}
 	args := &api.DescTableArgs{Database: database, Table: table}
-	return api.DescTable(c, args)
+	result, err := api.DescTable(c, args)
+	if err != nil {
+		return nil, err
+	}
+	c.schemaCache.put(database, table, result.Table)
+	return result, nil
 }
 
 func (c *Client) AddField(args *api.AddFieldArgs) error {
+	if !c.disableNameValidation {
+		if err := validateSchema(args.Schema); err != nil {
+			return err
+		}
+	}
+	if err := c.resolveArgs(&args); err != nil {
+		return err
+	}
+	defer c.schemaCache.invalidate(args.Database, args.Table)
 	return api.AddField(c, args)
 }
 
+// ModifyTable - change the description, the replication or the dynamic field of the table
+//
+// PARAMS:
+//   - args: the modify table args, at least one property should be set
+//
+// RETURNS:
+//   - error: nil if ok otherwise the specific error
+func (c *Client) ModifyTable(args *api.ModifyTableArgs) error {
+	if args.Description == nil && args.Replication == 0 && args.EnableDynamicField == nil {
+		return errors.New("no property of the table to modify")
+	}
+	if err := c.resolveArgs(&args); err != nil {
+		return err
+	}
+	defer c.schemaCache.invalidate(args.Database, args.Table)
+	return api.ModifyTable(c, args)
+}
+
 func (c *Client) AliasTable(database, table, alias string) error {
+	if !c.disableNameValidation {
+		if err := ValidateName("alias", alias); err != nil {
+			return err
+		}
+	}
+	defer c.aliases.invalidate(database, alias)
 	args := &api.AliasTableArgs{Database: database, Table: table, Alias: alias}
 	return api.AliasTable(c, args)
 }
 
 func (c *Client) UnaliasTable(database, table, alias string) error {
+	defer c.aliases.invalidate(database, alias)
 	args := &api.UnaliasTableArgs{Database: database, Table: table, Alias: alias}
 	return api.UnaliasTable(c, args)
 }
 
+func (c *Client) ListAliases(database string) (*api.ListAliasesResult, error) {
+	args := &api.ListAliasesArgs{Database: database}
+	return api.ListAliases(c, args)
+}
+
+// ResolveAlias - get the table the alias points at by the server, the result is cached if the
+// alias cache is enabled
+//
+// PARAMS:
+//   - database: the database name
+//   - alias: the alias name
+//
+// RETURNS:
+//   - string: the table name
+//   - error: ErrAliasNotExist if not found, otherwise nil if ok or the specific error
+func (c *Client) ResolveAlias(database, alias string) (string, error) {
+	if table, ok := c.aliases.get(database, alias); ok {
+		return table, nil
+	}
+	args := &api.DescAliasArgs{Database: database, Alias: alias}
+	result, err := api.DescAlias(c, args)
+	if api.IsAliasNotExist(err) {
+		return "", ErrAliasNotExist
+	}
+	if err != nil {
+		return "", err
+	}
+	c.aliases.put(database, alias, result.Table)
+	return result.Table, nil
+}
+
 func (c *Client) ShowTableStats(database, table string) (*api.ShowTableStatsResult, error) {
+	table, err := c.resolveTable(database, table)
+	if err != nil {
+		return nil, err
+	}
 	args := &api.ShowTableStatsArgs{Database: database, Table: table}
 	return api.ShowTableStats(c, args)
 }
 
+// CompactTable - start merging the segments of the table in the background, e.g. after a bulk
+// ingestion and before benchmarking or rebuilding the indexes
+//
+// PARAMS:
+//   - database: the database name
+//   - table: the table name
+//
+// RETURNS:
+//   - string: the id of the compaction task to wait for by WaitForTask
+//   - error: nil if ok otherwise the specific error
+func (c *Client) CompactTable(database, table string) (string, error) {
+	table, err := c.resolveTable(database, table)
+	if err != nil {
+		return "", err
+	}
+	args := &api.CompactTableArgs{Database: database, Table: table}
+	result, err := api.CompactTable(c, args)
+	if err != nil {
+		return "", err
+	}
+	return result.TaskID, nil
+}
+
+// FlushTable - start flushing the rows of the table in the memory to the segments
+//
+// PARAMS:
+//   - database: the database name
+//   - table: the table name
+//
+// RETURNS:
+//   - string: the id of the flush task to wait for by WaitForTask
+//   - error: nil if ok otherwise the specific error
+func (c *Client) FlushTable(database, table string) (string, error) {
+	table, err := c.resolveTable(database, table)
+	if err != nil {
+		return "", err
+	}
+	args := &api.FlushTableArgs{Database: database, Table: table}
+	result, err := api.FlushTable(c, args)
+	if err != nil {
+		return "", err
+	}
+	return result.TaskID, nil
+}
+
+func (c *Client) ShowStorageStatus(database, table string) (*api.ShowStorageStatusResult, error) {
+	table, err := c.resolveTable(database, table)
+	if err != nil {
+		return nil, err
+	}
+	args := &api.ShowStorageStatusArgs{Database: database, Table: table}
+	return api.ShowStorageStatus(c, args)
+}
+
+func (c *Client) DescPartitions(database, table string) (*api.DescPartitionsResult, error) {
+	table, err := c.resolveTable(database, table)
+	if err != nil {
+		return nil, err
+	}
+	args := &api.DescPartitionsArgs{Database: database, Table: table}
+	return api.DescPartitions(c, args)
+}
+
+// RebalancePartitions - start moving the partitions of the table among the data nodes to even
+// out their load, e.g. after the nodes are added, the server rejects it if not supported
+//
+// PARAMS:
+//   - database: the database name
+//   - table: the table name
+//
+// RETURNS:
+//   - string: the id of the rebalance task to wait for by WaitForTask
+//   - error: nil if ok otherwise the specific error
+func (c *Client) RebalancePartitions(database, table string) (string, error) {
+	table, err := c.resolveTable(database, table)
+	if err != nil {
+		return "", err
+	}
+	args := &api.RebalancePartitionsArgs{Database: database, Table: table}
+	result, err := api.RebalancePartitions(c, args)
+	if err != nil {
+		return "", err
+	}
+	return result.TaskID, nil
+}
+
+// ResplitPartitions - start redistributing the rows of the table to the new number of
+// partitions, the server rejects it if not supported
+//
+// PARAMS:
+//   - database: the database name
+//   - table: the table name
+//   - partitionNum: the number of partitions after the resplit
+//
+// RETURNS:
+//   - string: the id of the resplit task to wait for by WaitForTask
+//   - error: nil if ok otherwise the specific error
+func (c *Client) ResplitPartitions(database, table string, partitionNum uint32) (string, error) {
+	if partitionNum == 0 {
+		return "", errors.New("partition number should be positive")
+	}
+	table, err := c.resolveTable(database, table)
+	if err != nil {
+		return "", err
+	}
+	args := &api.ResplitPartitionsArgs{
+		Database:     database,
+		Table:        table,
+		PartitionNum: partitionNum,
+	}
+	defer c.schemaCache.invalidate(database, table)
+	result, err := api.ResplitPartitions(c, args)
+	if err != nil {
+		return "", err
+	}
+	return result.TaskID, nil
+}
+
 func (c *Client) CreateIndex(args *api.CreateIndexArgs) error {
+	if !c.disableArgsValidation {
+		if err := checkIndexArgs(args); err != nil {
+			return err
+		}
+	}
+	if !c.disableNameValidation {
+		for _, index := range args.Indexes {
+			if err := validateIndex(index); err != nil {
+				return err
+			}
+		}
+	}
+	if err := c.resolveArgs(&args); err != nil {
+		return err
+	}
 	return api.CreateIndex(c, args)
 }
 
 func (c *Client) DescIndex(database, table, indexName string) (*api.DescIndexResult, error) {
+	table, err := c.resolveTable(database, table)
+	if err != nil {
+		return nil, err
+	}
 	args := &api.DescIndexArgs{Database: database, Table: table, IndexName: indexName}
 	return api.DescIndex(c, args)
 }
 
+func (c *Client) ListIndexes(database, table string) ([]api.IndexSchema, error) {
+	table, err := c.resolveTable(database, table)
+	if err != nil {
+		return nil, err
+	}
+	return api.ListIndexes(c, database, table)
+}
+
+func (c *Client) HasIndex(database, table, indexName string) (bool, error) {
+	indexes, err := c.ListIndexes(database, table)
+	if err != nil {
+		return false, err
+	}
+	for _, index := range indexes {
+		if index.IndexName == indexName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (c *Client) ModifyIndex(args *api.ModifyIndexArgs) error {
+	if err := c.resolveArgs(&args); err != nil {
+		return err
+	}
 	return api.ModifyIndex(c, args)
 }
 
 func (c *Client) DropIndex(database, table, indexName string) error {
+	table, err := c.resolveTable(database, table)
+	if err != nil {
+		return err
+	}
 	return api.DropIndex(c, database, table, indexName)
 }
 
 func (c *Client) RebuildIndex(database, table, indexName string) error {
+	table, err := c.resolveTable(database, table)
+	if err != nil {
+		return err
+	}
 	args := &api.RebuildIndexArgs{Database: database, Table: table, IndexName: indexName}
 	return api.RebuildIndex(c, args)
 }
 
 func (c *Client) InsertRow(args *api.InsertRowArgs) (*api.InsertRowResult, error) {
+	if !c.disableArgsValidation {
+		if err := checkWriteArgs(args.Database, args.Table, args.Rows); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.resolveArgs(&args); err != nil {
+		return nil, err
+	}
+	if rows, err := c.encodeTimeRows(args.Database, args.Table, args.Rows); err != nil {
+		return nil, err
+	} else if rows != nil {
+		encoded := *args
+		encoded.Rows = rows
+		args = &encoded
+	}
+	if err := c.checkCachedRows(args.Database, args.Table, args.Rows); err != nil {
+		return nil, err
+	}
+	if c.queryCache != nil {
+		defer c.queryCache.invalidateRows(args.Database, args.Table, args.Rows)
+	}
 	return api.InsertRow(c, args)
 }
 
 func (c *Client) UpsertRow(args *api.UpsertRowArg) (*api.UpsertRowResult, error) {
+	if !c.disableArgsValidation {
+		if err := checkWriteArgs(args.Database, args.Table, args.Rows); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.resolveArgs(&args); err != nil {
+		return nil, err
+	}
+	if rows, err := c.encodeTimeRows(args.Database, args.Table, args.Rows); err != nil {
+		return nil, err
+	} else if rows != nil {
+		encoded := *args
+		encoded.Rows = rows
+		args = &encoded
+	}
+	if err := c.checkCachedRows(args.Database, args.Table, args.Rows); err != nil {
+		return nil, err
+	}
+	if c.queryCache != nil {
+		defer c.queryCache.invalidateRows(args.Database, args.Table, args.Rows)
+	}
+	if c.pipeline != nil && c.pipeline.accepts(c) {
+		return c.pipeline.upsert(args)
+	}
 	return api.UpsertRow(c, args)
 }
 
+// encodeTimeRows - format the time.Time values of the rows by the types of the fields, the schema
+// of the table is fetched only if any row has a time value, nil is returned if no row has one
+func (c *Client) encodeTimeRows(database, table string, rows []api.Row) ([]api.Row, error) {
+	i := 0
+	for i < len(rows) && !rows[i].HasTimes() {
+		i++
+	}
+	if i == len(rows) {
+		return nil, nil
+	}
+	description, err := c.tableSchema(database, table)
+	if err != nil {
+		return nil, err
+	}
+	var schema *api.TableSchema
+	if description != nil {
+		schema = description.Schema
+	}
+	encoded := make([]api.Row, len(rows))
+	for i := range rows {
+		encoded[i] = rows[i].EncodeTimes(schema)
+	}
+	return encoded, nil
+}
+
 func (c *Client) DeleteRow(args *api.DeleteRowArgs) error {
+	args, err := c.prepareDeleteRow(args)
+	if err != nil {
+		return err
+	}
+	defer c.invalidateDeleted(args)
 	return api.DeleteRow(c, args)
 }
 
+// DeleteRows - delete the rows of the primary key, the primary keys or the filter, and return the
+// number of the deleted rows
+//
+// PARAMS:
+//   - args: the delete args
+//
+// RETURNS:
+//   - *api.DeleteRowResult: the number of the deleted rows
+//   - error: nil if ok otherwise the specific error
+func (c *Client) DeleteRows(args *api.DeleteRowArgs) (*api.DeleteRowResult, error) {
+	args, err := c.prepareDeleteRow(args)
+	if err != nil {
+		return nil, err
+	}
+	defer c.invalidateDeleted(args)
+	return api.DeleteRows(c, args)
+}
+
+func (c *Client) prepareDeleteRow(args *api.DeleteRowArgs) (*api.DeleteRowArgs, error) {
+	if len(args.PrimaryKey) != 0 && len(args.PrimaryKeys) != 0 {
+		return nil, errors.New("primaryKey and primaryKeys should not be both set")
+	}
+	if err := c.validateFilter(args.Filter); err != nil {
+		return nil, err
+	}
+	if err := c.resolveArgs(&args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+// invalidateDeleted - drop the cached results of the deleted rows
+func (c *Client) invalidateDeleted(args *api.DeleteRowArgs) {
+	if c.queryCache == nil {
+		return
+	}
+	switch {
+	case len(args.PrimaryKey) != 0:
+		c.queryCache.invalidateKey(args.Database, args.Table, args.PrimaryKey)
+	case len(args.PrimaryKeys) != 0:
+		for _, primaryKey := range args.PrimaryKeys {
+			c.queryCache.invalidateKey(args.Database, args.Table, primaryKey)
+		}
+	default:
+		c.queryCache.invalidateTable(args.Database, args.Table)
+	}
+}
+
 func (c *Client) QueryRow(args *api.QueryRowArgs) (*api.QueryRowResult, error) {
-	return api.QueryRow(c, args)
+	if len(args.PrimaryKey) != 0 && len(args.PrimaryKeys) != 0 {
+		return nil, errors.New("primaryKey and primaryKeys should not be both set")
+	}
+	if err := c.resolveArgs(&args); err != nil {
+		return nil, err
+	}
+	// the cached results are shared by the callers, the credentials of a call may not read them
+	if c.queryCache == nil || !cacheable(args) || c.callOptions.credentials != nil {
+		return api.QueryRow(c, args)
+	}
+	if result, ok := c.queryCache.get(args); ok {
+		return result, nil
+	}
+	gen := c.queryCache.generation()
+	result, err := api.QueryRow(c, args)
+	if err != nil {
+		return nil, err
+	}
+	c.queryCache.put(args, result, gen)
+	return result, nil
+}
+
+// BatchQueryRow - query the rows of multiple primary keys in one request instead of one request
+// per key
+//
+// PARAMS:
+//   - args: the batch query args
+//
+// RETURNS:
+//   - *api.BatchQueryRowResult: the found rows
+//   - error: nil if ok otherwise the specific error
+func (c *Client) BatchQueryRow(args *api.BatchQueryRowArgs) (*api.BatchQueryRowResult, error) {
+	if len(args.PrimaryKeys) == 0 {
+		return nil, errors.New("primaryKeys should not be empty")
+	}
+	if len(args.PartitionKeys) != 0 && len(args.PartitionKeys) != len(args.PrimaryKeys) {
+		return nil, errors.New("partitionKeys should be aligned with primaryKeys")
+	}
+	if err := c.resolveArgs(&args); err != nil {
+		return nil, err
+	}
+	return api.BatchQueryRow(c, args)
 }
 
 func (c *Client) SearchRow(args *api.SearchRowArgs) (*api.SearchRowResult, error) {
+	if len(args.GroupBy) != 0 {
+		return nil, errors.New("grouped search should be sent by GroupSearchRow")
+	}
+	if args.ANNS != nil {
+		if err := args.ANNS.Validate(); err != nil {
+			return nil, err
+		}
+		if err := c.validateFilter(args.ANNS.Filter); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.resolveArgs(&args); err != nil {
+		return nil, err
+	}
 	return api.SearchRow(c, args)
 }
 
+// GroupSearchRow - search the nearest rows grouped by the GroupBy field of the args, at most
+// GroupSize rows of each group are returned
+//
+// PARAMS:
+//   - args: the search args, GroupBy should be set
+//
+// RETURNS:
+//   - *api.GroupSearchRowResult: the groups in the order of their nearest rows
+//   - error: nil if ok otherwise the specific error
+func (c *Client) GroupSearchRow(args *api.SearchRowArgs) (*api.GroupSearchRowResult, error) {
+	if len(args.GroupBy) == 0 {
+		return nil, errors.New("groupBy should not be empty")
+	}
+	if args.ANNS != nil {
+		if err := args.ANNS.Validate(); err != nil {
+			return nil, err
+		}
+		if err := c.validateFilter(args.ANNS.Filter); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.resolveArgs(&args); err != nil {
+		return nil, err
+	}
+	return api.GroupSearchRow(c, args)
+}
+
 func (c *Client) UpdateRow(args *api.UpdateRowArgs) error {
+	args, err := c.prepareUpdateRow(args)
+	if err != nil {
+		return err
+	}
+	defer c.invalidateUpdated(args)
 	return api.UpdateRow(c, args)
 }
 
+// UpdateRows - update the row of the primary key or all the rows matching the filter, and return
+// the number of the updated rows
+//
+// PARAMS:
+//   - args: the update args, either PrimaryKey or Filter should be set
+//
+// RETURNS:
+//   - *api.UpdateRowResult: the number of the updated rows
+//   - error: nil if ok otherwise the specific error
+func (c *Client) UpdateRows(args *api.UpdateRowArgs) (*api.UpdateRowResult, error) {
+	if len(args.PrimaryKey) == 0 && len(args.Filter) == 0 {
+		return nil, errors.New("primaryKey or filter should be set")
+	}
+	args, err := c.prepareUpdateRow(args)
+	if err != nil {
+		return nil, err
+	}
+	defer c.invalidateUpdated(args)
+	return api.UpdateRows(c, args)
+}
+
+func (c *Client) prepareUpdateRow(args *api.UpdateRowArgs) (*api.UpdateRowArgs, error) {
+	if len(args.PrimaryKey) != 0 && len(args.Filter) != 0 {
+		return nil, errors.New("primaryKey and filter should not be both set")
+	}
+	if err := c.validateFilter(args.Filter); err != nil {
+		return nil, err
+	}
+	if err := c.resolveArgs(&args); err != nil {
+		return nil, err
+	}
+	// the time.Time values of the update are formatted in the same way as the written rows
+	update := []api.Row{{Fields: args.Update}}
+	if rows, err := c.encodeTimeRows(args.Database, args.Table, update); err != nil {
+		return nil, err
+	} else if rows != nil {
+		encoded := *args
+		encoded.Update = rows[0].Fields
+		args = &encoded
+	}
+	return args, nil
+}
+
+// invalidateUpdated - drop the cached results of the updated rows
+func (c *Client) invalidateUpdated(args *api.UpdateRowArgs) {
+	if c.queryCache == nil {
+		return
+	}
+	if len(args.Filter) != 0 {
+		c.queryCache.invalidateTable(args.Database, args.Table)
+		return
+	}
+	c.queryCache.invalidateKey(args.Database, args.Table, args.PrimaryKey)
+}
+
 func (c *Client) SelectRow(args *api.SelectRowArgs) (*api.SelectRowResult, error) {
+	if err := c.validateFilter(args.Filter); err != nil {
+		return nil, err
+	}
+	if err := c.resolveArgs(&args); err != nil {
+		return nil, err
+	}
 	return api.SelectRow(c, args)
 }
 
+// Aggregate - compute the aggregates of the rows matching the filter on the server side, e.g. the
+// count, min, max and sum of the scalar fields, optionally grouped by the fields
+//
+// PARAMS:
+//   - args: the aggregate args
+//
+// RETURNS:
+//   - *api.AggregateResult: the aggregated groups
+//   - error: nil if ok otherwise the specific error
+func (c *Client) Aggregate(args *api.AggregateArgs) (*api.AggregateResult, error) {
+	if err := c.validateFilter(args.Filter); err != nil {
+		return nil, err
+	}
+	if err := c.resolveArgs(&args); err != nil {
+		return nil, err
+	}
+	return api.Aggregate(c, args)
+}
+
+// HybridSearchRow - search the rows by the vector and the keywords in one request, the results of
+// the ann search and the BM25 search are fused on the server side
+//
+// PARAMS:
+//   - args: the hybrid search args
+//
+// RETURNS:
+//   - *api.HybridSearchRowResult: the fused rows in the order of the scores
+//   - error: nil if ok otherwise the specific error
+func (c *Client) HybridSearchRow(args *api.HybridSearchRowArgs) (*api.HybridSearchRowResult, error) {
+	if args.Hybrid == nil {
+		return nil, errors.New("hybrid search params should not be nil")
+	}
+	if err := args.Hybrid.Validate(); err != nil {
+		return nil, err
+	}
+	if err := c.validateFilter(args.Hybrid.Filter); err != nil {
+		return nil, err
+	}
+	if err := c.validateFilter(args.Hybrid.ANNS.Filter); err != nil {
+		return nil, err
+	}
+	if err := c.resolveArgs(&args); err != nil {
+		return nil, err
+	}
+	return api.HybridSearchRow(c, args)
+}
+
+// SearchText - search the rows by the keywords over the inverted index, the rows are ranked by
+// the BM25 relevance
+//
+// PARAMS:
+//   - args: the text search args
+//
+// RETURNS:
+//   - *api.TextSearchRowResult: the matched rows in the order of the scores
+//   - error: nil if ok otherwise the specific error
+func (c *Client) SearchText(args *api.TextSearchRowArgs) (*api.TextSearchRowResult, error) {
+	if args.BM25 == nil {
+		return nil, errors.New("bm25 search params should not be nil")
+	}
+	if len(args.BM25.IndexName) == 0 || len(args.BM25.SearchText) == 0 {
+		return nil, errors.New("indexName and searchText of bm25 search should not be empty")
+	}
+	if err := c.validateFilter(args.Filter); err != nil {
+		return nil, err
+	}
+	if err := c.resolveArgs(&args); err != nil {
+		return nil, err
+	}
+	return api.TextSearchRow(c, args)
+}
+
+// MultiVectorSearchRow - search multiple vector fields of the table in one request, e.g. the
+// title and body vectors, the results are fused into one list on the server side
+//
+// PARAMS:
+//   - args: the multivector search args
+//
+// RETURNS:
+//   - *api.SearchRowResult: the fused rows, the Distance of each row is the fused score
+//   - error: nil if ok otherwise the specific error
+func (c *Client) MultiVectorSearchRow(args *api.MultiVectorSearchArgs) (*api.SearchRowResult, error) {
+	if err := args.Validate(); err != nil {
+		return nil, err
+	}
+	if err := c.validateFilter(args.Filter); err != nil {
+		return nil, err
+	}
+	for _, search := range args.Searches {
+		if err := c.validateFilter(search.ANNS.Filter); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.resolveArgs(&args); err != nil {
+		return nil, err
+	}
+	return api.MultiVectorSearchRow(c, args)
+}
+
+// RangeSearchRow - search a page of the rows within the distance range of the vector, the
+// NextMarker of the result continues the search if IsTruncated
+//
+// PARAMS:
+//   - args: the range search args
+//
+// RETURNS:
+//   - *api.RangeSearchResult: the page of the rows with the distances
+//   - error: nil if ok otherwise the specific error
+func (c *Client) RangeSearchRow(args *api.RangeSearchArgs) (*api.RangeSearchResult, error) {
+	if len(args.VectorField) == 0 || len(args.VectorFloats) == 0 {
+		return nil, errors.New("vector field and vector of range search should not be empty")
+	}
+	if err := c.validateFilter(args.Filter); err != nil {
+		return nil, err
+	}
+	if err := c.resolveArgs(&args); err != nil {
+		return nil, err
+	}
+	return api.RangeSearchRow(c, args)
+}
+
 func (c *Client) BatchSearchRow(args *api.BatchSearchRowArgs) (*api.BatchSearchRowResult, error) {
+	if args.ANNS != nil {
+		if err := c.validateFilter(args.ANNS.Filter); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.resolveArgs(&args); err != nil {
+		return nil, err
+	}
 	return api.BatchSearchRow(c, args)
 }
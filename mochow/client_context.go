@@ -0,0 +1,343 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// client_context.go - the context-aware variants of the Client methods
+
+package mochow
+
+import (
+	"context"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+// The XxxWithContext methods are the same as the Xxx methods except that the requests are
+// canceled or time-bounded by the ctx, including the waiting before the retries, and the trace
+// headers carried by the ctx are propagated. They are shortcuts of c.WithContext(ctx).Xxx(...).
+
+func (c *Client) DescClusterWithContext(ctx context.Context) (*api.DescClusterResult, error) {
+	return c.WithContext(ctx).DescCluster()
+}
+
+func (c *Client) ListNodesWithContext(ctx context.Context) (*api.ListNodesResult, error) {
+	return c.WithContext(ctx).ListNodes()
+}
+
+func (c *Client) ServerVersionWithContext(ctx context.Context) (*api.ServerVersion, error) {
+	return c.WithContext(ctx).ServerVersion()
+}
+
+func (c *Client) DescTaskWithContext(ctx context.Context, taskID string) (*api.Task, error) {
+	return c.WithContext(ctx).DescTask(taskID)
+}
+
+func (c *Client) ListTasksWithContext(ctx context.Context,
+	args *api.ListTasksArgs) (*api.ListTasksResult, error) {
+	return c.WithContext(ctx).ListTasks(args)
+}
+
+func (c *Client) CancelTaskWithContext(ctx context.Context, taskID string) error {
+	return c.WithContext(ctx).CancelTask(taskID)
+}
+
+func (c *Client) CreateDatabaseWithContext(ctx context.Context, database string) error {
+	return c.WithContext(ctx).CreateDatabase(database)
+}
+
+func (c *Client) DropDatabaseWithContext(ctx context.Context, database string) error {
+	return c.WithContext(ctx).DropDatabase(database)
+}
+
+func (c *Client) ListDatabaseWithContext(ctx context.Context) (*api.ListDatabaseResult, error) {
+	return c.WithContext(ctx).ListDatabase()
+}
+
+func (c *Client) HasDatabaseWithContext(ctx context.Context, database string) (bool, error) {
+	return c.WithContext(ctx).HasDatabase(database)
+}
+
+func (c *Client) CreateUserWithContext(ctx context.Context, username, password string) error {
+	return c.WithContext(ctx).CreateUser(username, password)
+}
+
+func (c *Client) DropUserWithContext(ctx context.Context, username string) error {
+	return c.WithContext(ctx).DropUser(username)
+}
+
+func (c *Client) ChangePasswordWithContext(ctx context.Context, username, password string) error {
+	return c.WithContext(ctx).ChangePassword(username, password)
+}
+
+func (c *Client) ListUsersWithContext(ctx context.Context) (*api.ListUsersResult, error) {
+	return c.WithContext(ctx).ListUsers()
+}
+
+func (c *Client) CreateRoleWithContext(ctx context.Context, role string) error {
+	return c.WithContext(ctx).CreateRole(role)
+}
+
+func (c *Client) DropRoleWithContext(ctx context.Context, role string) error {
+	return c.WithContext(ctx).DropRole(role)
+}
+
+func (c *Client) ListRolesWithContext(ctx context.Context) (*api.ListRolesResult, error) {
+	return c.WithContext(ctx).ListRoles()
+}
+
+func (c *Client) GrantRoleWithContext(ctx context.Context,
+	username string, roles ...string) error {
+	return c.WithContext(ctx).GrantRole(username, roles...)
+}
+
+func (c *Client) RevokeRoleWithContext(ctx context.Context,
+	username string, roles ...string) error {
+	return c.WithContext(ctx).RevokeRole(username, roles...)
+}
+
+func (c *Client) GrantPrivilegeWithContext(ctx context.Context, args *api.GrantPrivilegeArgs) error {
+	return c.WithContext(ctx).GrantPrivilege(args)
+}
+
+func (c *Client) RevokePrivilegeWithContext(ctx context.Context,
+	args *api.RevokePrivilegeArgs) error {
+	return c.WithContext(ctx).RevokePrivilege(args)
+}
+
+func (c *Client) DescDatabaseWithContext(ctx context.Context,
+	database string) (*api.DescDatabaseResult, error) {
+	return c.WithContext(ctx).DescDatabase(database)
+}
+
+func (c *Client) ShowDatabaseStatsWithContext(ctx context.Context,
+	database string) (*api.ShowDatabaseStatsResult, error) {
+	return c.WithContext(ctx).ShowDatabaseStats(database)
+}
+
+func (c *Client) CreateTableWithContext(ctx context.Context, args *api.CreateTableArgs) error {
+	return c.WithContext(ctx).CreateTable(args)
+}
+
+func (c *Client) DropTableWithContext(ctx context.Context, database, table string) error {
+	return c.WithContext(ctx).DropTable(database, table)
+}
+
+func (c *Client) ListTableWithContext(ctx context.Context,
+	database string) (*api.ListTableResult, error) {
+	return c.WithContext(ctx).ListTable(database)
+}
+
+func (c *Client) HasTableWithContext(ctx context.Context, database, table string) (bool, error) {
+	return c.WithContext(ctx).HasTable(database, table)
+}
+
+func (c *Client) DescTableWithContext(ctx context.Context,
+	database, table string) (*api.DescTableResult, error) {
+	return c.WithContext(ctx).DescTable(database, table)
+}
+
+func (c *Client) AddFieldWithContext(ctx context.Context, args *api.AddFieldArgs) error {
+	return c.WithContext(ctx).AddField(args)
+}
+
+func (c *Client) CloneTableSchemaWithContext(ctx context.Context,
+	srcDatabase, srcTable, dstDatabase, dstTable string) error {
+	return c.WithContext(ctx).CloneTableSchema(srcDatabase, srcTable, dstDatabase, dstTable)
+}
+
+func (c *Client) ModifyTableWithContext(ctx context.Context, args *api.ModifyTableArgs) error {
+	return c.WithContext(ctx).ModifyTable(args)
+}
+
+func (c *Client) AliasTableWithContext(ctx context.Context, database, table, alias string) error {
+	return c.WithContext(ctx).AliasTable(database, table, alias)
+}
+
+func (c *Client) ListAliasesWithContext(ctx context.Context,
+	database string) (*api.ListAliasesResult, error) {
+	return c.WithContext(ctx).ListAliases(database)
+}
+
+func (c *Client) ResolveAliasWithContext(ctx context.Context,
+	database, alias string) (string, error) {
+	return c.WithContext(ctx).ResolveAlias(database, alias)
+}
+
+func (c *Client) UnaliasTableWithContext(ctx context.Context, database, table, alias string) error {
+	return c.WithContext(ctx).UnaliasTable(database, table, alias)
+}
+
+func (c *Client) ShowTableStatsWithContext(ctx context.Context,
+	database, table string) (*api.ShowTableStatsResult, error) {
+	return c.WithContext(ctx).ShowTableStats(database, table)
+}
+
+func (c *Client) CompactTableWithContext(ctx context.Context,
+	database, table string) (string, error) {
+	return c.WithContext(ctx).CompactTable(database, table)
+}
+
+func (c *Client) FlushTableWithContext(ctx context.Context, database, table string) (string, error) {
+	return c.WithContext(ctx).FlushTable(database, table)
+}
+
+func (c *Client) ShowStorageStatusWithContext(ctx context.Context,
+	database, table string) (*api.ShowStorageStatusResult, error) {
+	return c.WithContext(ctx).ShowStorageStatus(database, table)
+}
+
+func (c *Client) DescPartitionsWithContext(ctx context.Context,
+	database, table string) (*api.DescPartitionsResult, error) {
+	return c.WithContext(ctx).DescPartitions(database, table)
+}
+
+func (c *Client) RebalancePartitionsWithContext(ctx context.Context,
+	database, table string) (string, error) {
+	return c.WithContext(ctx).RebalancePartitions(database, table)
+}
+
+func (c *Client) ResplitPartitionsWithContext(ctx context.Context,
+	database, table string, partitionNum uint32) (string, error) {
+	return c.WithContext(ctx).ResplitPartitions(database, table, partitionNum)
+}
+
+func (c *Client) CreateIndexWithContext(ctx context.Context, args *api.CreateIndexArgs) error {
+	return c.WithContext(ctx).CreateIndex(args)
+}
+
+func (c *Client) DescIndexWithContext(ctx context.Context,
+	database, table, indexName string) (*api.DescIndexResult, error) {
+	return c.WithContext(ctx).DescIndex(database, table, indexName)
+}
+
+func (c *Client) ListIndexesWithContext(ctx context.Context,
+	database, table string) ([]api.IndexSchema, error) {
+	return c.WithContext(ctx).ListIndexes(database, table)
+}
+
+func (c *Client) HasIndexWithContext(ctx context.Context,
+	database, table, indexName string) (bool, error) {
+	return c.WithContext(ctx).HasIndex(database, table, indexName)
+}
+
+func (c *Client) ModifyIndexWithContext(ctx context.Context, args *api.ModifyIndexArgs) error {
+	return c.WithContext(ctx).ModifyIndex(args)
+}
+
+func (c *Client) DropIndexWithContext(ctx context.Context,
+	database, table, indexName string) error {
+	return c.WithContext(ctx).DropIndex(database, table, indexName)
+}
+
+func (c *Client) RebuildIndexWithContext(ctx context.Context,
+	database, table, indexName string) error {
+	return c.WithContext(ctx).RebuildIndex(database, table, indexName)
+}
+
+func (c *Client) InsertRowWithContext(ctx context.Context,
+	args *api.InsertRowArgs) (*api.InsertRowResult, error) {
+	return c.WithContext(ctx).InsertRow(args)
+}
+
+func (c *Client) UpsertRowWithContext(ctx context.Context,
+	args *api.UpsertRowArg) (*api.UpsertRowResult, error) {
+	return c.WithContext(ctx).UpsertRow(args)
+}
+
+func (c *Client) DeleteRowWithContext(ctx context.Context, args *api.DeleteRowArgs) error {
+	return c.WithContext(ctx).DeleteRow(args)
+}
+
+func (c *Client) DeleteRowsWithContext(ctx context.Context,
+	args *api.DeleteRowArgs) (*api.DeleteRowResult, error) {
+	return c.WithContext(ctx).DeleteRows(args)
+}
+
+func (c *Client) QueryRowWithContext(ctx context.Context,
+	args *api.QueryRowArgs) (*api.QueryRowResult, error) {
+	return c.WithContext(ctx).QueryRow(args)
+}
+
+func (c *Client) BatchQueryRowWithContext(ctx context.Context,
+	args *api.BatchQueryRowArgs) (*api.BatchQueryRowResult, error) {
+	return c.WithContext(ctx).BatchQueryRow(args)
+}
+
+func (c *Client) SearchRowWithContext(ctx context.Context,
+	args *api.SearchRowArgs) (*api.SearchRowResult, error) {
+	return c.WithContext(ctx).SearchRow(args)
+}
+
+func (c *Client) GroupSearchRowWithContext(ctx context.Context,
+	args *api.SearchRowArgs) (*api.GroupSearchRowResult, error) {
+	return c.WithContext(ctx).GroupSearchRow(args)
+}
+
+func (c *Client) UpdateRowWithContext(ctx context.Context, args *api.UpdateRowArgs) error {
+	return c.WithContext(ctx).UpdateRow(args)
+}
+
+func (c *Client) UpdateRowsWithContext(ctx context.Context,
+	args *api.UpdateRowArgs) (*api.UpdateRowResult, error) {
+	return c.WithContext(ctx).UpdateRows(args)
+}
+
+func (c *Client) SelectRowWithContext(ctx context.Context,
+	args *api.SelectRowArgs) (*api.SelectRowResult, error) {
+	return c.WithContext(ctx).SelectRow(args)
+}
+
+func (c *Client) AggregateWithContext(ctx context.Context,
+	args *api.AggregateArgs) (*api.AggregateResult, error) {
+	return c.WithContext(ctx).Aggregate(args)
+}
+
+func (c *Client) HybridSearchRowWithContext(ctx context.Context,
+	args *api.HybridSearchRowArgs) (*api.HybridSearchRowResult, error) {
+	return c.WithContext(ctx).HybridSearchRow(args)
+}
+
+func (c *Client) SearchTextWithContext(ctx context.Context,
+	args *api.TextSearchRowArgs) (*api.TextSearchRowResult, error) {
+	return c.WithContext(ctx).SearchText(args)
+}
+
+func (c *Client) MultiVectorSearchRowWithContext(ctx context.Context,
+	args *api.MultiVectorSearchArgs) (*api.SearchRowResult, error) {
+	return c.WithContext(ctx).MultiVectorSearchRow(args)
+}
+
+func (c *Client) RangeSearchRowWithContext(ctx context.Context,
+	args *api.RangeSearchArgs) (*api.RangeSearchResult, error) {
+	return c.WithContext(ctx).RangeSearchRow(args)
+}
+
+func (c *Client) BatchSearchRowWithContext(ctx context.Context,
+	args *api.BatchSearchRowArgs) (*api.BatchSearchRowResult, error) {
+	return c.WithContext(ctx).BatchSearchRow(args)
+}
+
+func (c *Client) QueryWithContext(ctx context.Context, database, table string,
+	primaryKey map[string]interface{}, opts ...api.ReadOption) (*api.QueryRowResult, error) {
+	return c.WithContext(ctx).Query(database, table, primaryKey, opts...)
+}
+
+func (c *Client) SelectWithContext(ctx context.Context, database, table, filter string,
+	limit uint64, opts ...api.ReadOption) (*api.SelectRowResult, error) {
+	return c.WithContext(ctx).Select(database, table, filter, limit, opts...)
+}
+
+func (c *Client) SearchWithContext(ctx context.Context, database, table string,
+	anns *api.ANNSearchParams, opts ...api.ReadOption) (*api.SearchRowResult, error) {
+	return c.WithContext(ctx).Search(database, table, anns, opts...)
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// clone.go - the copy of the tables, e.g. from the staging to the production database
+
+package mochow
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+	"github.com/baidu/mochow-sdk-go/mochow/bulk"
+)
+
+// CloneTableOptions defines the options of CloneTable
+type CloneTableOptions struct {
+	// CopyRows copies the rows of the source table once the cloned table is ready
+	CopyRows bool
+	// Filter is the filter of the copied rows, all the rows are copied if empty
+	Filter string
+	// Export configures the pages and the retries of the selects of the source rows, the vector
+	// fields are always retrieved
+	Export ExportOptions
+	// Writer configures the batches and the retries of the upserts, its Database and Table are
+	// ignored
+	Writer bulk.WriterOptions
+	// Wait configures the waiting for the cloned table to be ready before copying the rows
+	Wait WaitOptions
+}
+
+// CloneTableSchema - create the table with the same fields, indexes and properties as the source
+// table, the server-populated properties such as the state and the create time are not copied
+//
+// PARAMS:
+//   - srcDatabase: the database of the source table
+//   - srcTable: the source table name
+//   - dstDatabase: the database of the cloned table
+//   - dstTable: the cloned table name
+//
+// RETURNS:
+//   - error: nil if ok otherwise the specific error
+func (c *Client) CloneTableSchema(srcDatabase, srcTable, dstDatabase, dstTable string) error {
+	desc, err := c.DescTable(srcDatabase, srcTable)
+	if err != nil {
+		return err
+	}
+	if desc.Table == nil || desc.Table.Schema == nil {
+		return fmt.Errorf("schema of table %s.%s not found", srcDatabase, srcTable)
+	}
+	return c.CreateTable(cloneTableArgs(desc.Table, dstDatabase, dstTable))
+}
+
+// cloneTableArgs - build the args to create the table like the described one
+func cloneTableArgs(table *api.TableDescription, database, name string) *api.CreateTableArgs {
+	schema := &api.TableSchema{
+		Fields:  append([]api.FieldSchema(nil), table.Schema.Fields...),
+		Indexes: make([]api.IndexSchema, len(table.Schema.Indexes)),
+	}
+	for i, index := range table.Schema.Indexes {
+		index.State = ""
+		schema.Indexes[i] = index
+	}
+	return &api.CreateTableArgs{
+		Database:           database,
+		Table:              name,
+		Description:        table.Description,
+		Replication:        table.Replication,
+		Partition:          table.Partition,
+		EnableDynamicField: table.EnableDynamicField,
+		Schema:             schema,
+		TTLField:           table.TTLField,
+		TTLSeconds:         table.TTLSeconds,
+	}
+}
+
+// CloneTable - clone the schema of the source table by CloneTableSchema, then copy the rows if
+// CopyRows of the options is set, the rows are selected page by page and upserted in batches.
+//
+// PARAMS:
+//   - ctx: the context to cancel the requests and the waiting
+//   - srcDatabase: the database of the source table
+//   - srcTable: the source table name
+//   - dstDatabase: the database of the cloned table
+//   - dstTable: the cloned table name
+//   - options: the options of the clone, nil means cloning the schema only
+//
+// RETURNS:
+//   - int64: the number of rows copied, it is set even if the copy fails
+//   - error: nil if ok otherwise the specific error
+func (c *Client) CloneTable(ctx context.Context, srcDatabase, srcTable, dstDatabase,
+	dstTable string, options *CloneTableOptions) (int64, error) {
+	if options == nil {
+		options = &CloneTableOptions{}
+	}
+	cli := c.WithContext(ctx)
+	if err := cli.CloneTableSchema(srcDatabase, srcTable, dstDatabase, dstTable); err != nil {
+		return 0, err
+	}
+	if !options.CopyRows {
+		return 0, nil
+	}
+	if err := cli.WaitForTableReady(ctx, dstDatabase, dstTable, &options.Wait); err != nil {
+		return 0, err
+	}
+
+	writerOptions := options.Writer
+	writerOptions.Database, writerOptions.Table = dstDatabase, dstTable
+	writer, err := bulk.NewBufferedWriter(cli, &writerOptions)
+	if err != nil {
+		return 0, err
+	}
+	exportOptions := options.Export
+	exportOptions.RetrieveVector = true
+	it := cli.ExportRows(ctx, srcDatabase, srcTable, options.Filter, &exportOptions)
+	var rows int64
+	var copyErr error
+	for it.Next() {
+		if copyErr = writer.Add(it.Row()); copyErr != nil {
+			break
+		}
+		rows++
+	}
+	if copyErr == nil {
+		copyErr = it.Err()
+	}
+	if err := writer.Close(); copyErr == nil {
+		copyErr = err
+	}
+	return rows, copyErr
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// codegen.go - generate the go struct of the rows from the table description
+
+// Package codegen generates the go structs of the rows from the schemas of the Mochow tables, the
+// fields are tagged with `mochow:"fieldName"` for the typed row mapping.
+package codegen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"strings"
+	"unicode"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+// TagName is the struct tag key of the field names
+const TagName = api.RowTagName
+
+// Options defines the options of the generation
+type Options struct {
+	// Package is the package name of the generated file, no package clause if empty
+	Package string
+	// TypeName is the name of the struct, use the camel case of the table name if empty
+	TypeName string
+	// JSONTags adds the json tags besides the mochow tags
+	JSONTags bool
+}
+
+var goTypes = map[api.FieldType]string{
+	api.FieldTypeBool:         "bool",
+	api.FieldTypeInt8:         "int8",
+	api.FieldTypeUint8:        "uint8",
+	api.FieldTypeInt16:        "int16",
+	api.FieldTypeUint16:       "uint16",
+	api.FieldTypeInt32:        "int32",
+	api.FieldTypeUint32:       "uint32",
+	api.FieldTypeInt64:        "int64",
+	api.FieldTypeUint64:       "uint64",
+	api.FieldTypeFloat:        "float32",
+	api.FieldTypeDouble:       "float64",
+	api.FieldTypeDate:         "string",
+	api.FieldTypeDatetime:     "string",
+	api.FieldTypeTimestamp:    "string",
+	api.FieldTypeString:       "string",
+	api.FieldTypeBinary:       "[]byte",
+	api.FieldTypeUUID:         "string",
+	api.FieldTypeText:         "string",
+	api.FieldTypeTextGBK:      "string",
+	api.FieldTypeTextGB18030:  "string",
+	api.FieldTypeJSON:         "map[string]interface{}",
+	api.FieldTypeFloatVector:  "[]float32",
+	api.FieldTypeBinaryVector: "[]byte",
+}
+
+// GoType - get the go type of the field type
+func GoType(fieldType api.FieldType) (string, bool) {
+	goType, ok := goTypes[fieldType]
+	return goType, ok
+}
+
+var initialisms = map[string]string{
+	"id": "ID", "uid": "UID", "uuid": "UUID", "url": "URL", "uri": "URI", "ip": "IP",
+	"json": "JSON", "http": "HTTP", "api": "API", "sql": "SQL",
+}
+
+// GoName - convert the snake case name to the exported camel case go name, e.g. book_id to BookID
+func GoName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if upper, ok := initialisms[strings.ToLower(part)]; ok {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	goName := b.String()
+	if len(goName) == 0 || !unicode.IsLetter([]rune(goName)[0]) {
+		goName = "F" + goName
+	}
+	return goName
+}
+
+// GenerateStruct - generate the formatted go source of the struct of the table rows. The nullable
+// fields are tagged with omitempty.
+//
+// PARAMS:
+//   - table: the table description, e.g. the result of DescTable
+//   - options: the options of the generation, nil means default
+//
+// RETURNS:
+//   - []byte: the formatted go source
+//   - error: nil if ok otherwise the specific error
+func GenerateStruct(table *api.TableDescription, options *Options) ([]byte, error) {
+	if table == nil || table.Schema == nil {
+		return nil, errors.New("table schema should not be nil")
+	}
+	if options == nil {
+		options = &Options{}
+	}
+	typeName := options.TypeName
+	if len(typeName) == 0 {
+		typeName = GoName(table.Table)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated from the schema of table %s.%s. DO NOT EDIT.\n\n",
+		table.Database, table.Table)
+	if len(options.Package) != 0 {
+		fmt.Fprintf(&buf, "package %s\n\n", options.Package)
+	}
+	fmt.Fprintf(&buf, "// %s is the row of table %s.%s\n", typeName, table.Database, table.Table)
+	fmt.Fprintf(&buf, "type %s struct {\n", typeName)
+	names := make(map[string]string, len(table.Schema.Fields))
+	for _, field := range table.Schema.Fields {
+		goType, ok := GoType(field.FieldType)
+		if !ok {
+			return nil, fmt.Errorf("unsupported type %s of field %s", field.FieldType, field.FieldName)
+		}
+		goName := GoName(field.FieldName)
+		if previous, ok := names[goName]; ok {
+			return nil, fmt.Errorf("fields %s and %s are both named %s", previous, field.FieldName, goName)
+		}
+		names[goName] = field.FieldName
+
+		tag := field.FieldName
+		if !field.NotNull && !field.PrimaryKey && !field.PartitionKey {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&buf, "\t%s %s `%s:%q", goName, goType, TagName, tag)
+		if options.JSONTags {
+			fmt.Fprintf(&buf, " json:%q", tag)
+		}
+		buf.WriteString("`\n")
+	}
+	buf.WriteString("}\n")
+	return format.Source(buf.Bytes())
+}
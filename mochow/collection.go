@@ -0,0 +1,183 @@
+//go:build go1.21
+
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// collection.go - the generic typed API of the rows of a table, built by Go 1.21 and later only:
+// the module declares go 1.17, and the toolchains before Go 1.21 compile the type parameters at
+// that language version and fail, while Go 1.21 upgrades the language version by the constraint
+
+package mochow
+
+import (
+	"context"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+// Collection is the typed view of the table, the rows are the structs T or the pointers to the
+// structs mapped by the `mochow` tags, see api.StructToRow. The Database and Table of the args
+// passed to the methods are ignored.
+type Collection[T any] struct {
+	cli      *Client
+	database string
+	table    string
+}
+
+// ScoredRow is a typed search result with its distance
+type ScoredRow[T any] struct {
+	Row      T
+	Distance float64
+}
+
+// NewCollection - create the typed view of the table
+//
+// PARAMS:
+//   - cli: the client to send the requests
+//   - database: the database name
+//   - table: the table name
+//
+// RETURNS:
+//   - *Collection[T]: the typed view of the table
+func NewCollection[T any](cli *Client, database, table string) *Collection[T] {
+	return &Collection[T]{cli: cli, database: database, table: table}
+}
+
+func (c *Collection[T]) Database() string {
+	return c.database
+}
+
+func (c *Collection[T]) Table() string {
+	return c.table
+}
+
+// Insert - insert the rows
+func (c *Collection[T]) Insert(ctx context.Context, rows ...T) (*api.InsertRowResult, error) {
+	return c.cli.InsertStructs(ctx, c.database, c.table, rows)
+}
+
+// Upsert - upsert the rows
+func (c *Collection[T]) Upsert(ctx context.Context, rows ...T) (*api.UpsertRowResult, error) {
+	return c.cli.UpsertStructs(ctx, c.database, c.table, rows)
+}
+
+// Query - query the row of the PrimaryKey or the rows of the PrimaryKeys of the args
+//
+// PARAMS:
+//   - ctx: the context to cancel the request
+//   - args: the query args
+//
+// RETURNS:
+//   - []T: the found rows
+//   - error: nil if ok otherwise the specific error
+func (c *Collection[T]) Query(ctx context.Context, args *api.QueryRowArgs) ([]T, error) {
+	queryArgs := api.QueryRowArgs{}
+	if args != nil {
+		queryArgs = *args
+	}
+	queryArgs.Database, queryArgs.Table = c.database, c.table
+	result, err := c.cli.WithContext(ctx).QueryRow(&queryArgs)
+	if err != nil {
+		return nil, err
+	}
+	rows := result.Rows
+	if len(queryArgs.PrimaryKeys) == 0 && len(result.Row.Fields) != 0 {
+		rows = []api.Row{result.Row}
+	}
+	var out []T
+	if err := api.DecodeRows(rows, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Search - search the rows by the vector in the order of the distances
+//
+// PARAMS:
+//   - ctx: the context to cancel the request
+//   - args: the search args
+//
+// RETURNS:
+//   - []T: the found rows
+//   - error: nil if ok otherwise the specific error
+func (c *Collection[T]) Search(ctx context.Context, args *api.SearchRowArgs) ([]T, error) {
+	result, err := c.search(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	var out []T
+	if err := result.DecodeRows(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SearchWithDistance - search the rows by the vector and return the rows with the distances
+func (c *Collection[T]) SearchWithDistance(ctx context.Context,
+	args *api.SearchRowArgs) ([]ScoredRow[T], error) {
+	result, err := c.search(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	var rows []T
+	if err := result.DecodeRows(&rows); err != nil {
+		return nil, err
+	}
+	out := make([]ScoredRow[T], len(rows))
+	for i := range rows {
+		out[i] = ScoredRow[T]{Row: rows[i], Distance: result.Rows[i].Distance}
+	}
+	return out, nil
+}
+
+func (c *Collection[T]) search(ctx context.Context,
+	args *api.SearchRowArgs) (*api.SearchRowResult, error) {
+	searchArgs := api.SearchRowArgs{}
+	if args != nil {
+		searchArgs = *args
+	}
+	searchArgs.Database, searchArgs.Table = c.database, c.table
+	return c.cli.WithContext(ctx).SearchRow(&searchArgs)
+}
+
+// Select - select a page of the rows
+//
+// PARAMS:
+//   - ctx: the context to cancel the request
+//   - args: the select args, its Limit is the page size and its Marker is the start of the rows
+//
+// RETURNS:
+//   - []T: the selected rows
+//   - map[string]interface{}: the marker of the next page, nil if no more rows
+//   - error: nil if ok otherwise the specific error
+func (c *Collection[T]) Select(ctx context.Context,
+	args *api.SelectRowArgs) ([]T, map[string]interface{}, error) {
+	selectArgs := api.SelectRowArgs{}
+	if args != nil {
+		selectArgs = *args
+	}
+	selectArgs.Database, selectArgs.Table = c.database, c.table
+	result, err := c.cli.WithContext(ctx).SelectRow(&selectArgs)
+	if err != nil {
+		return nil, nil, err
+	}
+	var out []T
+	if err := result.DecodeRows(&out); err != nil {
+		return nil, nil, err
+	}
+	if !result.IsTruncated {
+		return out, nil, nil
+	}
+	return out, result.NextMarker, nil
+}
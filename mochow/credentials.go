@@ -0,0 +1,109 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// credentials.go - the selection of the credentials of the requests, e.g. per database
+
+package mochow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/baidu/mochow-sdk-go/auth"
+	"github.com/baidu/mochow-sdk-go/client"
+)
+
+// CredentialProvider selects the credentials of the requests by their databases, e.g. the api
+// keys of the tenants of a multi-tenant application. The credentials of the client configuration
+// are used if it returns nil or the request has no database, e.g. ListDatabase.
+type CredentialProvider interface {
+	Credentials(database string) (*auth.BceCredentials, error)
+}
+
+// DatabaseCredentials is the CredentialProvider of the fixed credentials of the databases
+type DatabaseCredentials map[string]*auth.BceCredentials
+
+func (m DatabaseCredentials) Credentials(database string) (*auth.BceCredentials, error) {
+	return m[database], nil
+}
+
+// WithCredentials - derive a client which signs its requests with the credentials instead of the
+// credentials of the client configuration and the CredentialProvider, its QueryRow calls bypass
+// the query cache and its UpsertRow calls bypass the write pipeline
+//
+// PARAMS:
+//   - credentials: the credentials of the requests
+//
+// RETURNS:
+//   - *Client: the derived client sharing the connections and the caches with c
+func (c *Client) WithCredentials(credentials *auth.BceCredentials) *Client {
+	derived := *c
+	derived.callOptions.credentials = credentials
+	return &derived
+}
+
+// applyCredentials - set the credentials of the call or the database of the request if any
+func (c *Client) applyCredentials(req *client.BceRequest, content []byte) error {
+	if c.callOptions.credentials != nil {
+		req.SetCredentials(c.callOptions.credentials)
+		return nil
+	}
+	if c.credentialProvider == nil {
+		return nil
+	}
+	database := requestDatabase(req, content)
+	if len(database) == 0 {
+		return nil
+	}
+	credentials, err := c.credentialProvider.Credentials(database)
+	if err != nil {
+		return fmt.Errorf("get credentials of database %s failed: %w", database, err)
+	}
+	req.SetCredentials(credentials)
+	return nil
+}
+
+// requestDatabase - get the database of the request from its params or the top-level database
+// key of its json body, the tokens after the key are not scanned
+func requestDatabase(req *client.BceRequest, content []byte) string {
+	if database := req.Param("database"); len(database) != 0 {
+		return database
+	}
+	if len(content) == 0 {
+		return ""
+	}
+	dec := json.NewDecoder(bytes.NewReader(content))
+	if token, err := dec.Token(); err != nil || token != json.Delim('{') {
+		return ""
+	}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return ""
+		}
+		if key, _ := token.(string); key == "database" {
+			var database string
+			if err := dec.Decode(&database); err != nil {
+				return ""
+			}
+			return database
+		}
+		var skipped json.RawMessage
+		if err := dec.Decode(&skipped); err != nil {
+			return ""
+		}
+	}
+	return ""
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// delete_all.go - the paged delete of the rows matching a filter
+
+package mochow
+
+import (
+	"context"
+	"errors"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+const DefaultDeleteAllBatchSize = 1000
+
+// DeleteAllOptions defines the options of DeleteAll
+type DeleteAllOptions struct {
+	// BatchSize is the max number of rows selected in one page, use DefaultDeleteAllBatchSize if
+	// not positive
+	BatchSize uint64
+	// OnProgress is called with the total number of deleted rows after each page
+	OnProgress func(deleted uint64)
+}
+
+// tableKeys - get the primary key and partition key field names of the table
+func (c *Client) tableKeys(database, table string) (primaryKey, partitionKey []string, err error) {
+	result, err := c.DescTable(database, table)
+	if err != nil {
+		return nil, nil, err
+	}
+	if result.Table == nil || result.Table.Schema == nil {
+		return nil, nil, errors.New("schema of table " + table + " not found")
+	}
+	for _, field := range result.Table.Schema.Fields {
+		if field.PrimaryKey {
+			primaryKey = append(primaryKey, field.FieldName)
+		}
+		if field.PartitionKey {
+			partitionKey = append(partitionKey, field.FieldName)
+		}
+	}
+	if len(primaryKey) == 0 {
+		return nil, nil, errors.New("primary key of table " + table + " not found")
+	}
+	return primaryKey, partitionKey, nil
+}
+
+// DeleteAll - delete the rows matching the filter in bounded pages instead of one unbounded
+// filter delete which may time out. The primary keys of each page are selected and the rows of
+// the page are deleted by the primary keys, so the rows deleted before an error or the
+// cancellation are not restored.
+//
+// PARAMS:
+//   - ctx: the context to cancel the deletion
+//   - database: the database name
+//   - table: the table name
+//   - filter: the filter of the rows to delete, all the rows are deleted if empty
+//   - options: the options of the deletion, nil means default
+//
+// RETURNS:
+//   - uint64: the number of deleted rows
+//   - error: nil if ok otherwise the specific error
+func (c *Client) DeleteAll(ctx context.Context, database, table, filter string,
+	options *DeleteAllOptions) (uint64, error) {
+	if options == nil {
+		options = &DeleteAllOptions{}
+	}
+	c = c.WithContext(ctx)
+	batchSize := options.BatchSize
+	if batchSize == 0 {
+		batchSize = DefaultDeleteAllBatchSize
+	}
+	primaryKey, partitionKey, err := c.tableKeys(database, table)
+	if err != nil {
+		return 0, err
+	}
+	// the rows of a page are deleted by the primary keys in one request unless the partition key
+	// is not a part of the primary key, then each row is deleted with its partition key
+	batchDelete := true
+	projections := append([]string{}, primaryKey...)
+	for _, name := range partitionKey {
+		if !containsString(projections, name) {
+			projections = append(projections, name)
+			batchDelete = false
+		}
+	}
+
+	var deleted uint64
+	var marker map[string]interface{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return deleted, err
+		}
+		result, err := c.SelectRow(&api.SelectRowArgs{
+			Database:        database,
+			Table:           table,
+			Filter:          filter,
+			Marker:          marker,
+			Limit:           batchSize,
+			Projections:     projections,
+			ReadConsistency: api.STRONG,
+		})
+		if err != nil {
+			return deleted, err
+		}
+		if batchDelete {
+			n, err := c.deleteByKeys(database, table, result.Rows, primaryKey)
+			deleted += n
+			if err != nil {
+				return deleted, err
+			}
+		} else {
+			for _, row := range result.Rows {
+				if err := ctx.Err(); err != nil {
+					return deleted, err
+				}
+				args := &api.DeleteRowArgs{
+					Database:     database,
+					Table:        table,
+					PrimaryKey:   pickFields(row, primaryKey),
+					PartitionKey: pickFields(row, partitionKey),
+				}
+				if err := c.DeleteRow(args); err != nil {
+					return deleted, err
+				}
+				deleted++
+			}
+		}
+		if options.OnProgress != nil && len(result.Rows) != 0 {
+			options.OnProgress(deleted)
+		}
+		if !result.IsTruncated || len(result.NextMarker) == 0 {
+			return deleted, nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+// deleteByKeys - delete the rows by their primary keys in one request
+func (c *Client) deleteByKeys(database, table string, rows []api.Row,
+	primaryKey []string) (uint64, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	keys := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		keys[i] = pickFields(row, primaryKey)
+	}
+	result, err := c.DeleteRows(&api.DeleteRowArgs{
+		Database:    database,
+		Table:       table,
+		PrimaryKeys: keys,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.AffectedCount, nil
+}
+
+func pickFields(row api.Row, names []string) map[string]interface{} {
+	fields := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		fields[name] = row.Fields[name]
+	}
+	return fields
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
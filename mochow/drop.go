@@ -0,0 +1,75 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// drop.go - the helpers to drop the tables and databases and wait for the deletion
+
+package mochow
+
+import (
+	"context"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+// DropTableAndWait - drop the table and wait until it does not exist, it is ok if the table does
+// not exist before dropping
+//
+// PARAMS:
+//   - ctx: the context to cancel the requests and the waiting
+//   - database: the database name
+//   - table: the table name
+//
+// RETURNS:
+//   - error: nil if ok otherwise the specific error
+func (c *Client) DropTableAndWait(ctx context.Context, database, table string) error {
+	c = c.WithContext(ctx)
+	err := c.DropTable(database, table)
+	if api.IsTableNotExist(err) || api.IsDatabaseNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return c.WaitForTableDropped(ctx, database, table, nil)
+}
+
+// DropDatabaseCascade - drop all the tables of the database, wait for the deletion and then drop
+// the database, it is ok if the database does not exist
+//
+// PARAMS:
+//   - ctx: the context to cancel the requests and the waiting
+//   - database: the database name
+//
+// RETURNS:
+//   - error: nil if ok otherwise the specific error
+func (c *Client) DropDatabaseCascade(ctx context.Context, database string) error {
+	c = c.WithContext(ctx)
+	result, err := c.ListTable(database)
+	if api.IsDatabaseNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, table := range result.Tables {
+		if err := c.DropTableAndWait(ctx, database, table); err != nil {
+			return err
+		}
+	}
+	err = c.DropDatabase(database)
+	if api.IsDatabaseNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,144 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// ensure.go - the idempotent creators of the databases and tables
+
+package mochow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+// EnsureTableOptions defines the options of EnsureTable
+type EnsureTableOptions struct {
+	// WaitReady waits until the state of the table is NORMAL
+	WaitReady bool
+	// PollInterval is the interval to check the state of the table, use DefaultWaitPollInterval
+	// if not positive
+	PollInterval time.Duration
+}
+
+// SchemaMismatchError is returned by EnsureTable when the existing table is not compatible with
+// the expected schema
+type SchemaMismatchError struct {
+	Database    string
+	Table       string
+	Differences []string
+}
+
+func (e *SchemaMismatchError) Error() string {
+	return fmt.Sprintf("schema of table %s.%s mismatched: %s", e.Database, e.Table,
+		strings.Join(e.Differences, "; "))
+}
+
+// EnsureDatabase - create the database if it does not exist
+func (c *Client) EnsureDatabase(database string) error {
+	err := c.CreateDatabase(database)
+	if api.IsDatabaseAlreadyExist(err) {
+		return nil
+	}
+	return err
+}
+
+// EnsureTable - create the table if it does not exist, otherwise verify that the existing table
+// is compatible with the args, i.e. all the fields and indexes of the args exist with the same
+// definitions.
+//
+// PARAMS:
+//   - ctx: the context to cancel the requests and the waiting
+//   - args: the args to create the table
+//   - options: the options of the creation, nil means no waiting
+//
+// RETURNS:
+//   - error: *SchemaMismatchError if the existing table is not compatible, otherwise nil if ok
+//     or the specific error
+func (c *Client) EnsureTable(ctx context.Context, args *api.CreateTableArgs,
+	options *EnsureTableOptions) error {
+	if args == nil {
+		return errors.New("create table args should not be nil")
+	}
+	if options == nil {
+		options = &EnsureTableOptions{}
+	}
+	c = c.WithContext(ctx)
+	err := c.CreateTable(args)
+	if api.IsTableAlreadyExist(err) {
+		result, err := c.DescTable(args.Database, args.Table)
+		if err != nil {
+			return err
+		}
+		if diffs := diffTableSchema(args.Schema, result.Table); len(diffs) != 0 {
+			return &SchemaMismatchError{Database: args.Database, Table: args.Table, Differences: diffs}
+		}
+	} else if err != nil {
+		return err
+	}
+	if !options.WaitReady {
+		return nil
+	}
+	return c.WaitForTableReady(ctx, args.Database, args.Table,
+		&WaitOptions{PollInterval: options.PollInterval})
+}
+
+// diffTableSchema - list the fields and indexes of the expected schema which are missing or
+// different in the existing table
+func diffTableSchema(expected *api.TableSchema, existing *api.TableDescription) []string {
+	if expected == nil {
+		return nil
+	}
+	if existing == nil || existing.Schema == nil {
+		return []string{"schema of the existing table not found"}
+	}
+	var diffs []string
+	fields := make(map[string]api.FieldSchema, len(existing.Schema.Fields))
+	for _, field := range existing.Schema.Fields {
+		fields[field.FieldName] = field
+	}
+	for _, want := range expected.Fields {
+		got, ok := fields[want.FieldName]
+		switch {
+		case !ok:
+			diffs = append(diffs, "field "+want.FieldName+" not found")
+		case got.FieldType != want.FieldType:
+			diffs = append(diffs, fmt.Sprintf("field %s type %s, expected %s",
+				want.FieldName, got.FieldType, want.FieldType))
+		case got.PrimaryKey != want.PrimaryKey || got.PartitionKey != want.PartitionKey:
+			diffs = append(diffs, "field "+want.FieldName+" key definition mismatched")
+		case got.Dimension != want.Dimension:
+			diffs = append(diffs, fmt.Sprintf("field %s dimension %d, expected %d",
+				want.FieldName, got.Dimension, want.Dimension))
+		}
+	}
+	indexes := make(map[string]api.IndexSchema, len(existing.Schema.Indexes))
+	for _, index := range existing.Schema.Indexes {
+		indexes[index.IndexName] = index
+	}
+	for _, want := range expected.Indexes {
+		got, ok := indexes[want.IndexName]
+		switch {
+		case !ok:
+			diffs = append(diffs, "index "+want.IndexName+" not found")
+		case got.IndexType != want.IndexType || got.MetricType != want.MetricType ||
+			strings.Join(got.IndexedFields(), ",") != strings.Join(want.IndexedFields(), ","):
+			diffs = append(diffs, "index "+want.IndexName+" definition mismatched")
+		}
+	}
+	return diffs
+}
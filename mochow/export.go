@@ -0,0 +1,244 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// export.go - the iterator to stream the rows of a table page by page
+
+package mochow
+
+import (
+	"context"
+	"time"
+
+	"github.com/baidu/mochow-sdk-go/client"
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+const (
+	DefaultExportBatchSize     = 1000
+	DefaultExportMaxRetries    = 3
+	DefaultExportRetryInterval = time.Second
+)
+
+// ExportOptions defines the options of ExportRows
+type ExportOptions struct {
+	// BatchSize is the max number of rows in one page, use DefaultExportBatchSize if not positive
+	BatchSize       uint64
+	Projections     []string
+	ReadConsistency api.ReadConsistency
+	// MaxRetries is the max number of retries of a page for the retryable and the throttled errors
+	// besides the retries of the client, use DefaultExportMaxRetries if zero and no retry if
+	// negative
+	MaxRetries int
+	// RetryInterval is the interval between the retries, use DefaultExportRetryInterval if not
+	// positive
+	RetryInterval time.Duration
+	// Marker is the marker to start from, e.g. the Marker of a previous iterator
+	Marker map[string]interface{}
+	// OrderBy exports the rows in the order of the fields, e.g. the timestamp, instead of the
+	// primary key order
+	OrderBy []api.OrderByField
+	// ExcludeProjections exports all the fields except them if Projections is empty
+	ExcludeProjections []string
+	// RetrieveVector exports the vector fields as well
+	RetrieveVector bool
+}
+
+// RowIterator streams the rows of a table, only one page of rows is kept in memory. It is not
+// safe to be called by multiple goroutines. The usage is:
+//
+//	it := cli.ExportRows(ctx, database, table, filter, nil)
+//	for it.Next() {
+//		row := it.Row()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type RowIterator struct {
+	ctx     context.Context
+	cli     *Client
+	args    api.SelectRowArgs
+	options ExportOptions
+
+	rows   []api.Row
+	index  int
+	marker map[string]interface{}
+	done   bool
+	err    error
+}
+
+// ExportRows - create the iterator of the rows matching the filter, the rows are selected page by
+// page with the marker when iterating
+//
+// PARAMS:
+//   - ctx: the context to cancel the iteration
+//   - database: the database name
+//   - table: the table name
+//   - filter: the filter of the rows, all the rows are exported if empty
+//   - options: the options of the export, nil means default
+//
+// RETURNS:
+//   - *RowIterator: the iterator of the rows
+func (c *Client) ExportRows(ctx context.Context, database, table, filter string,
+	options *ExportOptions) *RowIterator {
+	if options == nil {
+		options = &ExportOptions{}
+	}
+	batchSize := options.BatchSize
+	if batchSize == 0 {
+		batchSize = DefaultExportBatchSize
+	}
+	return c.newRowIterator(ctx, api.SelectRowArgs{
+		Database:           database,
+		Table:              table,
+		Filter:             filter,
+		Marker:             options.Marker,
+		Limit:              batchSize,
+		Projections:        options.Projections,
+		ReadConsistency:    options.ReadConsistency,
+		OrderBy:            options.OrderBy,
+		ExcludeProjections: options.ExcludeProjections,
+		RetrieveVector:     options.RetrieveVector,
+	}, options)
+}
+
+// SelectAll - create the iterator of all the rows of the select args, the rows are selected page
+// by page following the NextMarker of the results when iterating
+//
+// PARAMS:
+//   - ctx: the context to cancel the iteration
+//   - args: the select args, its Limit is the page size and its Marker is the start of the rows
+//
+// RETURNS:
+//   - *RowIterator: the iterator of the rows
+func (c *Client) SelectAll(ctx context.Context, args *api.SelectRowArgs) *RowIterator {
+	pageArgs := *args
+	if pageArgs.Limit == 0 {
+		pageArgs.Limit = DefaultExportBatchSize
+	}
+	return c.newRowIterator(ctx, pageArgs, &ExportOptions{})
+}
+
+func (c *Client) newRowIterator(ctx context.Context, args api.SelectRowArgs,
+	options *ExportOptions) *RowIterator {
+	it := &RowIterator{ctx: ctx, cli: c.WithContext(ctx), args: args, options: *options,
+		marker: args.Marker}
+	if it.options.MaxRetries == 0 {
+		it.options.MaxRetries = DefaultExportMaxRetries
+	}
+	if it.options.RetryInterval <= 0 {
+		it.options.RetryInterval = DefaultExportRetryInterval
+	}
+	return it
+}
+
+// Next - advance to the next row, it returns false when there is no more row or an error occurs
+func (it *RowIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	it.index++
+	for it.index >= len(it.rows) {
+		if it.done {
+			it.rows = nil
+			return false
+		}
+		if err := it.fetch(); err != nil {
+			it.err = err
+			it.rows = nil
+			return false
+		}
+	}
+	return true
+}
+
+// Row - get the current row
+func (it *RowIterator) Row() api.Row {
+	return it.rows[it.index]
+}
+
+// Err - get the error stopped the iteration if any
+func (it *RowIterator) Err() error {
+	return it.err
+}
+
+// Marker - get the marker of the next page, the rows of the current page are not included
+func (it *RowIterator) Marker() map[string]interface{} {
+	return it.marker
+}
+
+// pageEnd - whether the current row is the last one of its page, the Marker is then the start of
+// the rows not iterated yet
+func (it *RowIterator) pageEnd() bool {
+	return it.index == len(it.rows)-1
+}
+
+// Stream - iterate the rows in a goroutine and send them to the returned channel, the channel has
+// buffer slots so that the iteration is blocked when the consumer falls behind. When the iteration
+// stops, the error channel receives the error if any, and both channels are closed. The iteration
+// stops once the context of the iterator is done.
+//
+// PARAMS:
+//   - buffer: the buffer size of the row channel
+//
+// RETURNS:
+//   - <-chan api.Row: the channel of the rows
+//   - <-chan error: the channel of the error stopped the iteration
+func (it *RowIterator) Stream(buffer int) (<-chan api.Row, <-chan error) {
+	if buffer < 0 {
+		buffer = 0
+	}
+	rows := make(chan api.Row, buffer)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(rows)
+		for it.Next() {
+			select {
+			case rows <- it.Row():
+			case <-it.ctx.Done():
+				errs <- it.ctx.Err()
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			errs <- err
+		}
+	}()
+	return rows, errs
+}
+
+// fetch - select the next page with the retries
+func (it *RowIterator) fetch() error {
+	args := it.args
+	args.Marker = it.marker
+	for retries := 0; ; retries++ {
+		if err := it.ctx.Err(); err != nil {
+			return err
+		}
+		result, err := it.cli.SelectRow(&args)
+		if err == nil {
+			it.rows = result.Rows
+			it.index = 0
+			it.marker = result.NextMarker
+			it.done = !result.IsTruncated || len(result.NextMarker) == 0
+			return nil
+		}
+		if retries >= it.options.MaxRetries ||
+			(!client.IsRetryable(err) && !client.IsThrottled(err)) {
+			return err
+		}
+		if err := sleepContext(it.ctx, it.options.RetryInterval); err != nil {
+			return err
+		}
+	}
+}
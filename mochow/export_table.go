@@ -0,0 +1,111 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// export_table.go - the export of the tables to the JSON Lines files
+
+package mochow
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+
+	"github.com/bytedance/sonic"
+)
+
+// ExportCheckpoint is the progress of ExportTable, all the rows before Marker have been written
+type ExportCheckpoint struct {
+	// Marker is the start of the rows not exported yet, pass it as the Marker of the export
+	// options to resume the export
+	Marker map[string]interface{} `json:"marker,omitempty"`
+	// Rows is the number of rows written by this export
+	Rows int64 `json:"rows"`
+	// Done is true if all the rows have been written
+	Done bool `json:"done"`
+}
+
+// ExportTableOptions defines the options of ExportTable
+type ExportTableOptions struct {
+	// Filter is the filter of the rows, all the rows are exported if empty
+	Filter string
+	// Export configures the pages, the projections and the retries of the selects, the export
+	// starts from its Marker
+	Export ExportOptions
+	// OnCheckpoint is called after each page is written and flushed to the writer, the export
+	// stops with the error if it returns one
+	OnCheckpoint func(checkpoint *ExportCheckpoint) error
+}
+
+// ExportTable - write the rows of the table to w as JSON Lines, i.e. one json object per line,
+// the rows are selected page by page and a checkpoint is reported after each page so that the
+// interrupted export is resumed from the marker of the last checkpoint.
+//
+// PARAMS:
+//   - ctx: the context to cancel the export
+//   - database: the database name
+//   - table: the table name
+//   - w: the destination of the rows
+//   - options: the options of the export, nil means default
+//
+// RETURNS:
+//   - int64: the number of rows written, it is set even if the export fails
+//   - error: nil if ok otherwise the specific error
+func (c *Client) ExportTable(ctx context.Context, database, table string, w io.Writer,
+	options *ExportTableOptions) (int64, error) {
+	if w == nil {
+		return 0, errors.New("writer should not be nil")
+	}
+	if options == nil {
+		options = &ExportTableOptions{}
+	}
+	it := c.ExportRows(ctx, database, table, options.Filter, &options.Export)
+	buf := bufio.NewWriter(w)
+	var rows int64
+	checkpoint := func(done bool) error {
+		if err := buf.Flush(); err != nil {
+			return err
+		}
+		if options.OnCheckpoint == nil {
+			return nil
+		}
+		var marker map[string]interface{}
+		if !done {
+			marker = it.Marker()
+		}
+		return options.OnCheckpoint(&ExportCheckpoint{Marker: marker, Rows: rows, Done: done})
+	}
+	for it.Next() {
+		row := it.Row()
+		line, err := sonic.Marshal(&row)
+		if err != nil {
+			return rows, err
+		}
+		if _, err := buf.Write(append(line, '\n')); err != nil {
+			return rows, err
+		}
+		rows++
+		if it.pageEnd() && !it.done {
+			if err := checkpoint(false); err != nil {
+				return rows, err
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		// keep the rows of the pages selected before
+		buf.Flush()
+		return rows, err
+	}
+	return rows, checkpoint(true)
+}
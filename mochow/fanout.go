@@ -0,0 +1,185 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// fanout.go - the helpers to run the same query against multiple tables concurrently
+
+package mochow
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+const DefaultFanOutConcurrency = 8
+
+// FanOutOptions defines the options of the multi-table queries
+type FanOutOptions struct {
+	// Concurrency is the number of workers, use DefaultFanOutConcurrency if not positive
+	Concurrency int
+	// DescendingDistance sorts the merged search results by the distance in descending order,
+	// e.g. for the IP metric, by default the results are sorted in ascending order
+	DescendingDistance bool
+	// Limit caps the number of merged search results, no limit if not positive
+	Limit int
+}
+
+// TableRowResult is a search result attributed to its source table
+type TableRowResult struct {
+	Table string
+	api.RowResult
+}
+
+// TableRow is a row attributed to its source table
+type TableRow struct {
+	Table string
+	Row   api.Row
+}
+
+// fanOut - call fn for each table with at most concurrency workers, stop dispatching once an
+// error occurs and return the first error
+func fanOut(tables []string, concurrency int, fn func(i int, table string) error) error {
+	if concurrency <= 0 {
+		concurrency = DefaultFanOutConcurrency
+	}
+	if concurrency > len(tables) {
+		concurrency = len(tables)
+	}
+	jobs := make(chan int)
+	failed := make(chan struct{})
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := fn(i, tables[i]); err != nil {
+					once.Do(func() {
+						firstErr = err
+						close(failed)
+					})
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for i := range tables {
+		select {
+		case jobs <- i:
+		case <-failed:
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return firstErr
+}
+
+// FanOutSearchRow - run the search against each table concurrently and merge the results sorted
+// by the distance, the Table of args is ignored
+//
+// PARAMS:
+//   - args: the search args shared by the tables
+//   - tables: the tables to search, e.g. the per-tenant tables
+//   - options: the options of the fan-out, nil means default
+//
+// RETURNS:
+//   - []TableRowResult: the merged results with the source tables
+//   - error: the first error of the requests if any
+func (c *Client) FanOutSearchRow(args *api.SearchRowArgs, tables []string,
+	options *FanOutOptions) ([]TableRowResult, error) {
+	if args == nil {
+		return nil, errors.New("search args should not be nil")
+	}
+	if args.ANNS != nil && args.ANNS.Offset != 0 {
+		// the offset of each table does not skip the nearer rows of the merged results
+		return nil, errors.New("offset is not supported by the fan-out search")
+	}
+	if options == nil {
+		options = &FanOutOptions{}
+	}
+	results := make([]*api.SearchRowResult, len(tables))
+	err := fanOut(tables, options.Concurrency, func(i int, table string) error {
+		tableArgs := *args
+		tableArgs.Table = table
+		result, err := c.SearchRow(&tableArgs)
+		results[i] = result
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var merged []TableRowResult
+	for i, result := range results {
+		for _, row := range result.Rows {
+			merged = append(merged, TableRowResult{Table: tables[i], RowResult: row})
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		if options.DescendingDistance {
+			return merged[i].Distance > merged[j].Distance
+		}
+		return merged[i].Distance < merged[j].Distance
+	})
+	if options.Limit > 0 && len(merged) > options.Limit {
+		merged = merged[:options.Limit]
+	}
+	return merged, nil
+}
+
+// FanOutQueryRow - query the row by the primary key in each table concurrently, the Table of args
+// is ignored
+//
+// PARAMS:
+//   - args: the query args shared by the tables
+//   - tables: the tables to query, e.g. the per-tenant tables
+//   - options: the options of the fan-out, nil means default
+//
+// RETURNS:
+//   - []TableRow: the rows found in the order of the tables
+//   - error: the first error of the requests if any
+func (c *Client) FanOutQueryRow(args *api.QueryRowArgs, tables []string,
+	options *FanOutOptions) ([]TableRow, error) {
+	if args == nil {
+		return nil, errors.New("query args should not be nil")
+	}
+	if options == nil {
+		options = &FanOutOptions{}
+	}
+	results := make([]*api.QueryRowResult, len(tables))
+	err := fanOut(tables, options.Concurrency, func(i int, table string) error {
+		tableArgs := *args
+		tableArgs.Table = table
+		result, err := c.QueryRow(&tableArgs)
+		results[i] = result
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []TableRow
+	for i, result := range results {
+		if len(result.Row.Fields) != 0 {
+			rows = append(rows, TableRow{Table: tables[i], Row: result.Row})
+		}
+	}
+	return rows, nil
+}
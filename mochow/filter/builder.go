@@ -0,0 +1,306 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// builder.go - the builder of the filter expressions with the escaped literals
+
+package filter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// the precedences of the expressions, the operands of lower precedences are parenthesized
+const (
+	precOr = iota + 1
+	precAnd
+	precUnary
+)
+
+// Expr is a filter expression built by the functions of the package, e.g.
+//
+//	expr := filter.And(filter.Eq("bookName", "三国演义"), filter.Range("page", 10, 100))
+//	text, err := expr.Build() // bookName = '三国演义' AND page >= 10 AND page <= 100
+//
+// The zero Expr is empty and it is skipped by And and Or. An invalid field or value is
+// reported by Build.
+type Expr struct {
+	text string
+	prec int
+	err  error
+}
+
+// String returns the filter text, it is empty if the expression is invalid
+func (e Expr) String() string {
+	if e.err != nil {
+		return ""
+	}
+	return e.text
+}
+
+// Build - render the filter text
+//
+// RETURNS:
+//   - string: the filter text, e.g. the Filter of the select args
+//   - error: nil if ok otherwise the first invalid field or value
+func (e Expr) Build() (string, error) {
+	return e.String(), e.err
+}
+
+// IsEmpty returns true if the expression has no condition
+func (e Expr) IsEmpty() bool {
+	return len(e.text) == 0 && e.err == nil
+}
+
+func invalid(format string, args ...interface{}) Expr {
+	return Expr{err: fmt.Errorf(format, args...)}
+}
+
+// Eq - the field equals the value, the value is a string, bool or number
+func Eq(field string, value interface{}) Expr {
+	return compare(field, "=", value)
+}
+
+// Ne - the field does not equal the value
+func Ne(field string, value interface{}) Expr {
+	return compare(field, "!=", value)
+}
+
+// Gt - the field is greater than the value
+func Gt(field string, value interface{}) Expr {
+	return compare(field, ">", value)
+}
+
+// Ge - the field is greater than or equal to the value
+func Ge(field string, value interface{}) Expr {
+	return compare(field, ">=", value)
+}
+
+// Lt - the field is less than the value
+func Lt(field string, value interface{}) Expr {
+	return compare(field, "<", value)
+}
+
+// Le - the field is less than or equal to the value
+func Le(field string, value interface{}) Expr {
+	return compare(field, "<=", value)
+}
+
+func compare(field, op string, value interface{}) Expr {
+	if err := checkField(field); err != nil {
+		return Expr{err: err}
+	}
+	literal, err := Quote(value)
+	if err != nil {
+		return invalid("invalid value of field %s: %w", field, err)
+	}
+	return Expr{text: field + " " + op + " " + literal, prec: precUnary}
+}
+
+// In - the field equals one of the values, the list should not be empty
+func In(field string, values ...interface{}) Expr {
+	return in(field, "IN", values)
+}
+
+// NotIn - the field equals none of the values, the list should not be empty
+func NotIn(field string, values ...interface{}) Expr {
+	return in(field, "NOT IN", values)
+}
+
+func in(field, op string, values []interface{}) Expr {
+	if err := checkField(field); err != nil {
+		return Expr{err: err}
+	}
+	if len(values) == 0 {
+		return invalid("values of %s %s should not be empty", field, op)
+	}
+	literals := make([]string, len(values))
+	for i, value := range values {
+		literal, err := Quote(value)
+		if err != nil {
+			return invalid("invalid value %d of field %s: %w", i, field, err)
+		}
+		literals[i] = literal
+	}
+	return Expr{text: field + " " + op + " [" + strings.Join(literals, ", ") + "]", prec: precUnary}
+}
+
+// Like - the string field matches the pattern, '%' matches any characters and '_' matches one
+func Like(field, pattern string) Expr {
+	if err := checkField(field); err != nil {
+		return Expr{err: err}
+	}
+	return Expr{text: field + " LIKE " + quoteString(pattern), prec: precUnary}
+}
+
+// Range - the field is in the closed interval [min, max], the bound is open if it is nil
+func Range(field string, min, max interface{}) Expr {
+	switch {
+	case min == nil && max == nil:
+		return invalid("bounds of the range of field %s should not be both nil", field)
+	case min == nil:
+		return Le(field, max)
+	case max == nil:
+		return Ge(field, min)
+	}
+	return And(Ge(field, min), Le(field, max))
+}
+
+// JSONPath - the path of the nested value of the JSON field, e.g. JSONPath("attrs", "color") is
+// "attrs.color", it is used as the field of the comparisons
+func JSONPath(field string, keys ...string) string {
+	return strings.Join(append([]string{field}, keys...), ".")
+}
+
+// And - all the expressions are true, the empty expressions are skipped
+func And(exprs ...Expr) Expr {
+	return join(" AND ", precAnd, exprs)
+}
+
+// Or - any of the expressions is true, the empty expressions are skipped
+func Or(exprs ...Expr) Expr {
+	return join(" OR ", precOr, exprs)
+}
+
+func join(sep string, prec int, exprs []Expr) Expr {
+	var parts []string
+	var last Expr
+	for _, expr := range exprs {
+		if expr.err != nil {
+			return expr
+		}
+		if expr.IsEmpty() {
+			continue
+		}
+		last = expr
+		parts = append(parts, parenthesize(expr, prec))
+	}
+	if len(parts) <= 1 {
+		return last
+	}
+	return Expr{text: strings.Join(parts, sep), prec: prec}
+}
+
+// Not - the expression is false
+func Not(expr Expr) Expr {
+	if expr.err != nil {
+		return expr
+	}
+	if expr.IsEmpty() {
+		return invalid("operand of NOT should not be empty")
+	}
+	return Expr{text: "NOT (" + expr.text + ")", prec: precUnary}
+}
+
+func parenthesize(expr Expr, prec int) string {
+	if expr.prec < prec {
+		return "(" + expr.text + ")"
+	}
+	return expr.text
+}
+
+// checkField - check that the field is an identifier of the filter rather than a keyword
+func checkField(field string) error {
+	if len(field) == 0 {
+		return errors.New("field should not be empty")
+	}
+	if !isIdentStart(field[0]) {
+		return fmt.Errorf("invalid field %q", field)
+	}
+	for i := 1; i < len(field); i++ {
+		if b := field[i]; !isIdentStart(b) && !isDigit(b) && b != '.' {
+			return fmt.Errorf("invalid field %q", field)
+		}
+	}
+	if _, ok := keywords[strings.ToUpper(field)]; ok {
+		return fmt.Errorf("keyword %s can not be used as a field", field)
+	}
+	return nil
+}
+
+// Quote - render the value as a literal of the filter, the strings are single-quoted with the
+// backslashes and quotes escaped
+//
+// PARAMS:
+//   - value: the string, bool, integer, float or json.Number value
+//
+// RETURNS:
+//   - string: the literal
+//   - error: nil if ok otherwise the unsupported value
+func Quote(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return quoteString(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int8:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int16:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float32:
+		return formatFloat(float64(v), 32)
+	case float64:
+		return formatFloat(v, 64)
+	case json.Number:
+		if end, ok := scanNumber(string(v), 0); len(v) == 0 || !ok || end != len(v) {
+			return "", fmt.Errorf("invalid number %q", string(v))
+		}
+		return string(v), nil
+	case nil:
+		return "", errors.New("null is not supported")
+	default:
+		return "", fmt.Errorf("unsupported value type %T", value)
+	}
+}
+
+func formatFloat(f float64, bitSize int) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("invalid number %v", f)
+	}
+	return strconv.FormatFloat(f, 'g', -1, bitSize), nil
+}
+
+func quoteString(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+	b.WriteByte('\'')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\'' || s[i] == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
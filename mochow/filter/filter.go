@@ -0,0 +1,183 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// filter.go - the client side validation of the filter expressions
+
+// Package filter parses the filter expressions of the select, search and delete requests, so that
+// the syntax errors and the unknown fields are reported with the positions before sending. The
+// grammar is:
+//
+//	expr       := and ("OR" and)*
+//	and        := not ("AND" not)*
+//	not        := "NOT" not | "(" expr ")" | comparison
+//	comparison := field op literal | field ["NOT"] "IN" list | field ["NOT"] "LIKE" string
+//	op         := "==" | "=" | "!=" | "<>" | ">" | ">=" | "<" | "<="
+//	list       := ("(" | "[") literal ("," literal)* (")" | "]")
+//	literal    := number | string | "TRUE" | "FALSE"
+//
+// The keywords are case insensitive, and "&&", "||" and "!" are accepted as well. The expressions
+// can be built by Eq, In, Range, And, Or, Not, etc. with the literals escaped instead of by hand.
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+// Error reports the invalid filter with the byte offset of the error
+type Error struct {
+	Filter  string
+	Pos     int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid filter at position %d: %s", e.Pos, e.Message)
+}
+
+// Comparison is a comparison of the field parsed from the filter
+type Comparison struct {
+	Field string
+	// Pos is the byte offset of the field in the filter
+	Pos      int
+	Operator string
+	Literals []Literal
+}
+
+// Literal is a literal value compared with the field
+type Literal struct {
+	Kind  LiteralKind
+	Value string
+	Pos   int
+}
+
+type LiteralKind int
+
+const (
+	NumberLiteral LiteralKind = iota
+	StringLiteral
+	BoolLiteral
+)
+
+// Parse - check the syntax of the filter and return its comparisons, the empty filter is valid
+//
+// PARAMS:
+//   - filter: the filter expression
+//
+// RETURNS:
+//   - []Comparison: the comparisons in the order of the filter
+//   - error: *Error if invalid otherwise nil
+func Parse(filter string) ([]Comparison, error) {
+	tokens, err := tokenize(filter)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{filter: filter, tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, nil
+	}
+	if err := p.parseExpr(); err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorf(tok, "unexpected %s", tok)
+	}
+	return p.comparisons, nil
+}
+
+// Validate - check the syntax of the filter
+func Validate(filter string) error {
+	_, err := Parse(filter)
+	return err
+}
+
+// ValidateForTable - check the syntax of the filter, and check the fields against the schema of
+// the table. The unknown fields are allowed if the dynamic field is enabled.
+//
+// PARAMS:
+//   - filter: the filter expression
+//   - table: the table description, e.g. the result of DescTable
+//
+// RETURNS:
+//   - error: *Error if invalid otherwise nil
+func ValidateForTable(filter string, table *api.TableDescription) error {
+	comparisons, err := Parse(filter)
+	if err != nil || table == nil || table.Schema == nil {
+		return err
+	}
+	fields := make(map[string]api.FieldType, len(table.Schema.Fields))
+	for _, field := range table.Schema.Fields {
+		fields[field.FieldName] = field.FieldType
+	}
+	for _, cmp := range comparisons {
+		fieldType, ok := fields[cmp.Field]
+		if !ok {
+			// the nested values of the JSON fields are not typed
+			if dot := strings.IndexByte(cmp.Field, '.'); dot > 0 &&
+				fields[cmp.Field[:dot]] == api.FieldTypeJSON {
+				continue
+			}
+			if table.EnableDynamicField {
+				continue
+			}
+			return &Error{Filter: filter, Pos: cmp.Pos, Message: "unknown field " + cmp.Field}
+		}
+		for _, literal := range cmp.Literals {
+			if msg := checkType(cmp, fieldType, literal); len(msg) != 0 {
+				return &Error{Filter: filter, Pos: literal.Pos, Message: msg}
+			}
+		}
+	}
+	return nil
+}
+
+func checkType(cmp Comparison, fieldType api.FieldType, literal Literal) string {
+	var expected LiteralKind
+	switch fieldType {
+	case api.FieldTypeFloatVector, api.FieldTypeBinaryVector:
+		return fmt.Sprintf("vector field %s can not be filtered", cmp.Field)
+	case api.FieldTypeJSON:
+		return fmt.Sprintf("JSON field %s can only be filtered by the paths of its values",
+			cmp.Field)
+	case api.FieldTypeBool:
+		expected = BoolLiteral
+	case api.FieldTypeInt8, api.FieldTypeUint8, api.FieldTypeInt16, api.FieldTypeUint16,
+		api.FieldTypeInt32, api.FieldTypeUint32, api.FieldTypeInt64, api.FieldTypeUint64,
+		api.FieldTypeFloat, api.FieldTypeDouble:
+		expected = NumberLiteral
+	default:
+		expected = StringLiteral
+	}
+	if strings.HasSuffix(cmp.Operator, "LIKE") && expected != StringLiteral {
+		return fmt.Sprintf("LIKE is not supported by the %s field %s", fieldType, cmp.Field)
+	}
+	if literal.Kind != expected {
+		return fmt.Sprintf("%s %s does not match the %s field %s",
+			literal.Kind, literal.Value, fieldType, cmp.Field)
+	}
+	return ""
+}
+
+func (k LiteralKind) String() string {
+	switch k {
+	case NumberLiteral:
+		return "number"
+	case StringLiteral:
+		return "string"
+	default:
+		return "bool"
+	}
+}
@@ -0,0 +1,347 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// parser.go - the tokenizer and the recursive descent parser of the filter expressions
+
+package filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenLBracket
+	tokenRBracket
+	tokenComma
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenIn
+	tokenLike
+	tokenBool
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of filter"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+var keywords = map[string]tokenKind{
+	"AND":   tokenAnd,
+	"OR":    tokenOr,
+	"NOT":   tokenNot,
+	"IN":    tokenIn,
+	"LIKE":  tokenLike,
+	"TRUE":  tokenBool,
+	"FALSE": tokenBool,
+}
+
+func isIdentStart(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b == '_'
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+func tokenize(filter string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(filter); {
+		b := filter[i]
+		start := i
+		switch {
+		case b == ' ' || b == '\t' || b == '\n' || b == '\r':
+			i++
+			continue
+		case isIdentStart(b):
+			for i < len(filter) && (isIdentStart(filter[i]) || isDigit(filter[i]) || filter[i] == '.') {
+				i++
+			}
+			text := filter[start:i]
+			kind, ok := keywords[strings.ToUpper(text)]
+			if !ok {
+				kind = tokenIdent
+			}
+			tokens = append(tokens, token{kind: kind, text: text, pos: start})
+			continue
+		case isDigit(b) || (b == '-' || b == '+' || b == '.') && i+1 < len(filter) &&
+			(isDigit(filter[i+1]) || filter[i+1] == '.'):
+			end, ok := scanNumber(filter, i)
+			if !ok {
+				return nil, &Error{Filter: filter, Pos: start, Message: "invalid number"}
+			}
+			i = end
+			tokens = append(tokens, token{kind: tokenNumber, text: filter[start:i], pos: start})
+			continue
+		case b == '\'' || b == '"':
+			i++
+			for i < len(filter) && filter[i] != b {
+				if filter[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(filter) {
+				return nil, &Error{Filter: filter, Pos: start, Message: "unterminated string"}
+			}
+			i++
+			tokens = append(tokens, token{kind: tokenString, text: filter[start:i], pos: start})
+			continue
+		}
+
+		kind, text := tokenOperator, ""
+		for _, op := range []string{"==", "!=", "<>", ">=", "<=", "&&", "||", ">", "<", "=", "!"} {
+			if strings.HasPrefix(filter[i:], op) {
+				text = op
+				break
+			}
+		}
+		switch text {
+		case "&&":
+			kind = tokenAnd
+		case "||":
+			kind = tokenOr
+		case "!":
+			kind = tokenNot
+		case "":
+			switch b {
+			case '(':
+				kind = tokenLParen
+			case ')':
+				kind = tokenRParen
+			case '[':
+				kind = tokenLBracket
+			case ']':
+				kind = tokenRBracket
+			case ',':
+				kind = tokenComma
+			default:
+				return nil, &Error{Filter: filter, Pos: start,
+					Message: fmt.Sprintf("unexpected character %q", b)}
+			}
+			text = filter[i : i+1]
+		}
+		i += len(text)
+		tokens = append(tokens, token{kind: kind, text: text, pos: start})
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(filter)}), nil
+}
+
+// scanNumber - scan the decimal number with the optional sign, fraction and exponent
+func scanNumber(s string, i int) (int, bool) {
+	if s[i] == '-' || s[i] == '+' {
+		i++
+	}
+	digits := 0
+	for ; i < len(s) && isDigit(s[i]); i++ {
+		digits++
+	}
+	if i < len(s) && s[i] == '.' {
+		for i++; i < len(s) && isDigit(s[i]); i++ {
+			digits++
+		}
+	}
+	if digits == 0 {
+		return i, false
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '-' || s[i] == '+') {
+			i++
+		}
+		if i >= len(s) || !isDigit(s[i]) {
+			return i, false
+		}
+		for i < len(s) && isDigit(s[i]) {
+			i++
+		}
+	}
+	if i < len(s) && (isIdentStart(s[i]) || s[i] == '.') {
+		return i, false
+	}
+	return i, true
+}
+
+type parser struct {
+	filter      string
+	tokens      []token
+	next        int
+	comparisons []Comparison
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) advance() token {
+	tok := p.tokens[p.next]
+	if tok.kind != tokenEOF {
+		p.next++
+	}
+	return tok
+}
+
+func (p *parser) errorf(tok token, format string, args ...interface{}) error {
+	return &Error{Filter: p.filter, Pos: tok.pos, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) parseExpr() error {
+	if err := p.parseAnd(); err != nil {
+		return err
+	}
+	for p.peek().kind == tokenOr {
+		p.advance()
+		if err := p.parseAnd(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseAnd() error {
+	if err := p.parseNot(); err != nil {
+		return err
+	}
+	for p.peek().kind == tokenAnd {
+		p.advance()
+		if err := p.parseNot(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseNot() error {
+	switch tok := p.peek(); tok.kind {
+	case tokenNot:
+		p.advance()
+		return p.parseNot()
+	case tokenLParen:
+		p.advance()
+		if err := p.parseExpr(); err != nil {
+			return err
+		}
+		if closing := p.advance(); closing.kind != tokenRParen {
+			return p.errorf(closing, "expected \")\" to close the \"(\" at position %d, got %s",
+				tok.pos, closing)
+		}
+		return nil
+	case tokenIdent:
+		return p.parseComparison()
+	default:
+		return p.errorf(tok, "expected a field, \"(\" or NOT, got %s", tok)
+	}
+}
+
+func (p *parser) parseComparison() error {
+	field := p.advance()
+	cmp := Comparison{Field: field.text, Pos: field.pos}
+	negated := false
+	if p.peek().kind == tokenNot {
+		p.advance()
+		negated = true
+	}
+	switch tok := p.advance(); {
+	case tok.kind == tokenIn:
+		cmp.Operator = "IN"
+		literals, err := p.parseList()
+		if err != nil {
+			return err
+		}
+		cmp.Literals = literals
+	case tok.kind == tokenLike:
+		cmp.Operator = "LIKE"
+		literal := p.advance()
+		if literal.kind != tokenString {
+			return p.errorf(literal, "expected a string pattern after LIKE, got %s", literal)
+		}
+		cmp.Literals = []Literal{{Kind: StringLiteral, Value: literal.text, Pos: literal.pos}}
+	case tok.kind == tokenOperator && !negated:
+		cmp.Operator = tok.text
+		literal, err := p.parseLiteral()
+		if err != nil {
+			return err
+		}
+		cmp.Literals = []Literal{literal}
+	case negated:
+		return p.errorf(tok, "expected IN or LIKE after NOT, got %s", tok)
+	default:
+		return p.errorf(tok, "expected a comparison operator after field %s, got %s", field.text, tok)
+	}
+	if negated {
+		cmp.Operator = "NOT " + cmp.Operator
+	}
+	p.comparisons = append(p.comparisons, cmp)
+	return nil
+}
+
+func (p *parser) parseList() ([]Literal, error) {
+	open := p.advance()
+	var closing tokenKind
+	switch open.kind {
+	case tokenLParen:
+		closing = tokenRParen
+	case tokenLBracket:
+		closing = tokenRBracket
+	default:
+		return nil, p.errorf(open, "expected \"(\" or \"[\" after IN, got %s", open)
+	}
+	var literals []Literal
+	for {
+		literal, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		literals = append(literals, literal)
+		tok := p.advance()
+		if tok.kind == closing {
+			return literals, nil
+		}
+		if tok.kind != tokenComma {
+			return nil, p.errorf(tok, "expected \",\" or the end of the list, got %s", tok)
+		}
+	}
+}
+
+func (p *parser) parseLiteral() (Literal, error) {
+	tok := p.advance()
+	switch tok.kind {
+	case tokenNumber:
+		return Literal{Kind: NumberLiteral, Value: tok.text, Pos: tok.pos}, nil
+	case tokenString:
+		return Literal{Kind: StringLiteral, Value: tok.text, Pos: tok.pos}, nil
+	case tokenBool:
+		return Literal{Kind: BoolLiteral, Value: tok.text, Pos: tok.pos}, nil
+	default:
+		return Literal{}, p.errorf(tok, "expected a number, string or bool, got %s", tok)
+	}
+}
@@ -0,0 +1,380 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// import.go - the streaming import of the rows from the JSON Lines and CSV files
+
+package mochow
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/sonic/decoder"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+	"github.com/baidu/mochow-sdk-go/mochow/bulk"
+)
+
+// ImportFormat is the format of the imported file
+type ImportFormat string
+
+const (
+	// ImportFormatJSONL is one json object per line, the blank lines are skipped
+	ImportFormatJSONL ImportFormat = "JSONL"
+	// ImportFormatCSV is the comma-separated values with the column names in the first record
+	ImportFormatCSV ImportFormat = "CSV"
+)
+
+const DefaultImportCheckpointInterval = 10000
+
+// ImportOptions defines the options of ImportFile
+type ImportOptions struct {
+	// Writer configures the batches, the retries and the progress callbacks of the bulk writer,
+	// its Database and Table are ignored
+	Writer bulk.WriterOptions
+	// ColumnMapping maps the column names or the json keys to the field names, the names not
+	// mapped are used as is
+	ColumnMapping map[string]string
+	// SkipUnknownColumns drops the columns which are not the fields of the table, by default they
+	// are rejected unless the dynamic field of the table is enabled
+	SkipUnknownColumns bool
+	// Comma is the separator of the CSV fields, use ',' if zero
+	Comma rune
+	// CheckpointStore saves the progress under ImportID so that the failed import is resumed by
+	// calling ImportFile again with the same source, the records already written by the Rows of the
+	// checkpoint are read and skipped, the checkpoint is deleted once done
+	CheckpointStore bulk.CheckpointStore
+	ImportID        string
+	// CheckpointInterval saves the checkpoint every CheckpointInterval records, use
+	// DefaultImportCheckpointInterval if not positive
+	CheckpointInterval int64
+}
+
+// ImportResult is the result of ImportFile
+type ImportResult struct {
+	// Rows is the number of rows imported by this call
+	Rows int64
+	// Skipped is the number of records skipped as imported by the resumed checkpoint
+	Skipped int64
+}
+
+// ImportFile - import the rows of the JSON Lines or CSV file into the table, the columns are
+// mapped to the fields of the table schema and the values are converted to the field types, e.g.
+// the CSV value "[0.1, 0.2]" of a FLOAT_VECTOR field, then the rows are upserted in batches.
+//
+// PARAMS:
+//   - ctx: the context to cancel the import
+//   - database: the database name
+//   - table: the table name
+//   - r: the source of the file
+//   - format: the format of the file
+//   - options: the options of the import, nil means default
+//
+// RETURNS:
+//   - *ImportResult: the number of rows imported, it is set even if the import fails
+//   - error: nil if ok otherwise the specific error
+func (c *Client) ImportFile(ctx context.Context, database, table string, r io.Reader,
+	format ImportFormat, options *ImportOptions) (*ImportResult, error) {
+	if r == nil {
+		return nil, errors.New("reader should not be nil")
+	}
+	if options == nil {
+		options = &ImportOptions{}
+	}
+	if options.CheckpointStore != nil && len(options.ImportID) == 0 {
+		return nil, errors.New("import id should not be empty when checkpoint store is set")
+	}
+	cli := c.WithContext(ctx)
+	desc, err := cli.DescTable(database, table)
+	if err != nil {
+		return nil, err
+	}
+	if desc.Table == nil || desc.Table.Schema == nil {
+		return nil, fmt.Errorf("schema of table %s.%s not found", database, table)
+	}
+	converter := newImportConverter(desc.Table, options)
+
+	var next func() (map[string]interface{}, error)
+	switch format {
+	case ImportFormatJSONL:
+		next = newJSONLReader(r)
+	case ImportFormatCSV:
+		if next, err = newCSVReader(r, options.Comma); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported import format: %s", format)
+	}
+
+	var skip int64
+	if options.CheckpointStore != nil {
+		checkpoint, err := options.CheckpointStore.Load(options.ImportID)
+		if err != nil {
+			return nil, err
+		}
+		if checkpoint != nil {
+			// each record is one row, the rows of the checkpoint are the records to skip
+			skip = checkpoint.Rows
+		}
+	}
+	interval := options.CheckpointInterval
+	if interval <= 0 {
+		interval = DefaultImportCheckpointInterval
+	}
+
+	writerOptions := options.Writer
+	writerOptions.Database, writerOptions.Table = database, table
+	writer, err := bulk.NewBufferedWriter(cli, &writerOptions)
+	if err != nil {
+		return nil, err
+	}
+	result := &ImportResult{}
+	var offset int64
+	importErr := func() error {
+		for {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			record, err := next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("read record %d failed: %w", offset+1, err)
+			}
+			offset++
+			if offset <= skip {
+				result.Skipped++
+				continue
+			}
+			row, err := converter.convert(record)
+			if err != nil {
+				return fmt.Errorf("convert record %d failed: %w", offset, err)
+			}
+			if err := writer.Add(row); err != nil {
+				return err
+			}
+			result.Rows++
+			if options.CheckpointStore != nil && offset%interval == 0 {
+				err := writer.Checkpoint(options.CheckpointStore, options.ImportID,
+					&bulk.Checkpoint{Rows: offset})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}()
+	if err := writer.Close(); importErr == nil {
+		importErr = err
+	}
+	if importErr != nil {
+		return result, importErr
+	}
+	if options.CheckpointStore != nil {
+		if err := options.CheckpointStore.Delete(options.ImportID); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func newJSONLReader(r io.Reader) func() (map[string]interface{}, error) {
+	reader := bufio.NewReader(r)
+	return func() (map[string]interface{}, error) {
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) == 0 && err != nil {
+				return nil, err
+			}
+			if err != nil && err != io.EOF {
+				return nil, err
+			}
+			line = bytes.TrimSpace(line)
+			if len(line) == 0 {
+				continue
+			}
+			record := make(map[string]interface{})
+			dec := decoder.NewStreamDecoder(bytes.NewReader(line))
+			dec.UseNumber()
+			if err := dec.Decode(&record); err != nil {
+				return nil, err
+			}
+			return record, nil
+		}
+	}
+}
+
+func newCSVReader(r io.Reader, comma rune) (func() (map[string]interface{}, error), error) {
+	reader := csv.NewReader(r)
+	if comma != 0 {
+		reader.Comma = comma
+	}
+	reader.ReuseRecord = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("header of the csv file not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := append([]string(nil), header...)
+	return func() (map[string]interface{}, error) {
+		values, err := reader.Read()
+		if err != nil {
+			return nil, err
+		}
+		record := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			// the empty values are null
+			if len(values[i]) != 0 {
+				record[column] = values[i]
+			}
+		}
+		return record, nil
+	}, nil
+}
+
+// importConverter converts the records to the rows by the table schema
+type importConverter struct {
+	fields  map[string]api.FieldSchema
+	mapping map[string]string
+	dynamic bool
+	skip    bool
+}
+
+func newImportConverter(table *api.TableDescription, options *ImportOptions) *importConverter {
+	fields := make(map[string]api.FieldSchema, len(table.Schema.Fields))
+	for _, field := range table.Schema.Fields {
+		fields[field.FieldName] = field
+	}
+	return &importConverter{
+		fields:  fields,
+		mapping: options.ColumnMapping,
+		dynamic: table.EnableDynamicField,
+		skip:    options.SkipUnknownColumns,
+	}
+}
+
+func (c *importConverter) convert(record map[string]interface{}) (api.Row, error) {
+	row := api.Row{Fields: make(map[string]interface{}, len(record))}
+	for column, value := range record {
+		name := column
+		if mapped, ok := c.mapping[column]; ok {
+			name = mapped
+		}
+		field, ok := c.fields[name]
+		if !ok {
+			if c.skip {
+				continue
+			}
+			if !c.dynamic {
+				return api.Row{}, fmt.Errorf("unknown column %s", column)
+			}
+			row.Fields[name] = value
+			continue
+		}
+		converted, err := convertImportValue(field.FieldType, value)
+		if err != nil {
+			return api.Row{}, fmt.Errorf("convert field %s failed: %w", name, err)
+		}
+		row.Fields[name] = converted
+	}
+	return row, nil
+}
+
+// convertImportValue - convert the json value or the csv string to the value of the field type
+func convertImportValue(fieldType api.FieldType, value interface{}) (interface{}, error) {
+	s, isString := value.(string)
+	switch fieldType {
+	case api.FieldTypeBool:
+		if isString {
+			return strconv.ParseBool(strings.TrimSpace(s))
+		}
+	case api.FieldTypeInt8, api.FieldTypeInt16, api.FieldTypeInt32, api.FieldTypeInt64:
+		if isString {
+			s = strings.TrimSpace(s)
+			if _, err := strconv.ParseInt(s, 10, 64); err != nil {
+				return nil, err
+			}
+			return json.Number(s), nil
+		}
+	case api.FieldTypeUint8, api.FieldTypeUint16, api.FieldTypeUint32, api.FieldTypeUint64:
+		if isString {
+			s = strings.TrimSpace(s)
+			if _, err := strconv.ParseUint(s, 10, 64); err != nil {
+				return nil, err
+			}
+			return json.Number(s), nil
+		}
+	case api.FieldTypeFloat, api.FieldTypeDouble:
+		if isString {
+			s = strings.TrimSpace(s)
+			if _, err := strconv.ParseFloat(s, 64); err != nil {
+				return nil, err
+			}
+			return json.Number(s), nil
+		}
+	case api.FieldTypeFloatVector:
+		return convertFloatVector(value)
+	case api.FieldTypeJSON:
+		if isString {
+			object := make(map[string]interface{})
+			dec := decoder.NewStreamDecoder(strings.NewReader(s))
+			dec.UseNumber()
+			if err := dec.Decode(&object); err != nil {
+				return nil, fmt.Errorf("invalid json object %q: %w", s, err)
+			}
+			return object, nil
+		}
+	}
+	return value, nil
+}
+
+// convertFloatVector - convert the json array or the csv string of the json array to the vector
+func convertFloatVector(value interface{}) ([]float32, error) {
+	if s, ok := value.(string); ok {
+		var elems []interface{}
+		dec := decoder.NewStreamDecoder(strings.NewReader(s))
+		dec.UseNumber()
+		if err := dec.Decode(&elems); err != nil {
+			return nil, fmt.Errorf("invalid vector %q: %w", s, err)
+		}
+		value = elems
+	}
+	elems, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expect an array as the vector but got %T", value)
+	}
+	vector := make([]float32, len(elems))
+	for i, elem := range elems {
+		n, ok := elem.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("element %d of the vector is not a number", i)
+		}
+		f, err := strconv.ParseFloat(string(n), 32)
+		if err != nil {
+			return nil, fmt.Errorf("element %d of the vector: %w", i, err)
+		}
+		vector[i] = float32(f)
+	}
+	return vector, nil
+}
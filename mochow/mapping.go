@@ -0,0 +1,73 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// mapping.go - the row methods of the go structs tagged with the field names
+
+package mochow
+
+import (
+	"context"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+// InsertStructs - insert the structs as the rows, the fields are mapped by the `mochow` tags, see
+// api.StructToRow
+//
+// PARAMS:
+//   - ctx: the context to cancel the request
+//   - database: the database name
+//   - table: the table name
+//   - rows: the slice of the structs or the pointers to the structs
+//
+// RETURNS:
+//   - *api.InsertRowResult: the result of the insertion
+//   - error: nil if ok otherwise the specific error
+func (c *Client) InsertStructs(ctx context.Context, database, table string,
+	rows interface{}) (*api.InsertRowResult, error) {
+	converted, err := api.StructsToRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	return c.WithContext(ctx).InsertRow(&api.InsertRowArgs{
+		Database: database,
+		Table:    table,
+		Rows:     converted,
+	})
+}
+
+// UpsertStructs - upsert the structs as the rows, the fields are mapped by the `mochow` tags, see
+// api.StructToRow
+//
+// PARAMS:
+//   - ctx: the context to cancel the request
+//   - database: the database name
+//   - table: the table name
+//   - rows: the slice of the structs or the pointers to the structs
+//
+// RETURNS:
+//   - *api.UpsertRowResult: the result of the upsert
+//   - error: nil if ok otherwise the specific error
+func (c *Client) UpsertStructs(ctx context.Context, database, table string,
+	rows interface{}) (*api.UpsertRowResult, error) {
+	converted, err := api.StructsToRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	return c.WithContext(ctx).UpsertRow(&api.UpsertRowArg{
+		Database: database,
+		Table:    table,
+		Rows:     converted,
+	})
+}
@@ -0,0 +1,371 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// pipeline.go - the pipelined write mode which coalesces small upserts into one request
+
+package mochow
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/baidu/mochow-sdk-go/client"
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+const (
+	DefaultPipelineMaxDelay = 5 * time.Millisecond
+	DefaultPipelineMaxRows  = 1000
+)
+
+// WritePipelineOptions defines the options of the pipelined write mode. When it is enabled, the
+// UpsertRow calls to the same table arriving within MaxDelay are coalesced into one request. Each
+// caller still blocks until the merged request finishes and gets its own result and error:
+//   - the rows of the same primary key are never sent in the same merged request, the primary
+//     key fields of a table are taken from its schema, which is fetched by DescTable once per
+//     table if the schema cache is disabled, the rows are sent unmerged if the schema can not be
+//     fetched
+//   - the calls of different TTLSeconds are never merged, and the rows keep their own TTLSeconds
+//   - if the rows of the merged request are rejected by the server, e.g. an invalid row, the
+//     rows of each caller are sent again separately, so that a bad row fails its own caller only,
+//     other errors, e.g. throttling or server errors, are returned to every caller
+//   - the PrimaryKeys of the result are the keys of the caller's rows, the AffectedCount is the
+//     number of the caller's rows if the server affected all the rows of the merged request,
+//     otherwise the rows of each caller are sent again separately for its own affected count
+//
+// The UpsertRow calls of a client derived with the call options, e.g. WithRawResponse, bypass the
+// pipeline and are sent as is.
+type WritePipelineOptions struct {
+	// MaxDelay is the max time to wait for more rows, use DefaultPipelineMaxDelay if not positive
+	MaxDelay time.Duration
+	// MaxRows flushes the pending rows immediately once reached, use DefaultPipelineMaxRows if not
+	// positive
+	MaxRows int
+}
+
+type pipelineKey struct {
+	database string
+	table    string
+	// the rows of the different ttls are sent in the different batches
+	ttlSeconds uint64
+}
+
+// pipelineWrite is the rows of an UpsertRow call and its result
+type pipelineWrite struct {
+	rows []api.Row
+	// keys are the primary keys of the rows, empty if the primary key fields are not given, e.g.
+	// the auto-increment primary key
+	keys   []string
+	result *api.UpsertRowResult
+	err    error
+}
+
+type pipelineBatch struct {
+	writes []*pipelineWrite
+	keys   map[string]struct{}
+	rows   int
+	timer  *time.Timer
+	done   chan struct{}
+}
+
+// conflicts returns true if any row of the write has the same primary key as a row of the batch
+func (b *pipelineBatch) conflicts(write *pipelineWrite) bool {
+	for _, key := range write.keys {
+		if _, ok := b.keys[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *pipelineBatch) add(write *pipelineWrite) {
+	b.writes = append(b.writes, write)
+	b.rows += len(write.rows)
+	for _, key := range write.keys {
+		b.keys[key] = struct{}{}
+	}
+}
+
+type writePipeline struct {
+	cli      *Client
+	maxDelay time.Duration
+	maxRows  int
+
+	mu       sync.Mutex
+	pending  map[pipelineKey]*pipelineBatch
+	pkFields map[string][]string // table key -> primary key field names
+	fetchMu  sync.Mutex
+}
+
+func newWritePipeline(cli *Client, options *WritePipelineOptions) *writePipeline {
+	p := &writePipeline{
+		cli:      cli,
+		maxDelay: options.MaxDelay,
+		maxRows:  options.MaxRows,
+		pending:  make(map[pipelineKey]*pipelineBatch),
+		pkFields: make(map[string][]string),
+	}
+	if p.maxDelay <= 0 {
+		p.maxDelay = DefaultPipelineMaxDelay
+	}
+	if p.maxRows <= 0 {
+		p.maxRows = DefaultPipelineMaxRows
+	}
+	return p
+}
+
+// accepts returns true if the upserts of the client can be sent by the pipeline, i.e. the client
+// is the one the pipeline is created for rather than a client derived from it
+func (p *writePipeline) accepts(c *Client) bool {
+	return c == p.cli
+}
+
+// upsert - append the rows to the pending batch of the table and wait for the batch to be sent,
+// the pending batch is sent first if it has any primary key of the rows
+func (p *writePipeline) upsert(args *api.UpsertRowArg) (*api.UpsertRowResult, error) {
+	key := pipelineKey{args.Database, args.Table, args.TTLSeconds}
+	write := &pipelineWrite{rows: args.Rows}
+	keys, ok := p.primaryKeys(args.Database, args.Table, args.Rows)
+	if !ok {
+		// the rows can not be told apart from the others without the schema
+		p.sendWrite(key, write)
+		return write.result, write.err
+	}
+	write.keys = keys
+
+	for {
+		p.mu.Lock()
+		batch, ok := p.pending[key]
+		if ok && batch.conflicts(write) {
+			delete(p.pending, key)
+			batch.timer.Stop()
+			p.mu.Unlock()
+			// the rows of the same primary keys are upserted after the pending ones
+			p.send(key, batch)
+			continue
+		}
+		if !ok {
+			batch = &pipelineBatch{keys: make(map[string]struct{}), done: make(chan struct{})}
+			p.pending[key] = batch
+			batch.timer = time.AfterFunc(p.maxDelay, func() { p.flush(key, batch) })
+		}
+		batch.add(write)
+		full := batch.rows >= p.maxRows
+		if full {
+			delete(p.pending, key)
+			batch.timer.Stop()
+		}
+		p.mu.Unlock()
+
+		if full {
+			p.send(key, batch)
+		}
+		<-batch.done
+		return write.result, write.err
+	}
+}
+
+// primaryKeys - get the primary keys of the rows by the primary key fields of the table, the
+// rows without all the primary key fields have no keys, false if the fields are unknown
+func (p *writePipeline) primaryKeys(database, table string, rows []api.Row) ([]string, bool) {
+	names, ok := p.primaryKeyFields(database, table)
+	if !ok {
+		return nil, false
+	}
+	if len(names) == 0 {
+		return nil, true
+	}
+	keys := make([]string, 0, len(rows))
+	for _, row := range rows {
+		var b strings.Builder
+		b.WriteString(pipelineTableKey(database, table))
+		complete := true
+		for _, name := range names {
+			value, ok := row.Fields[name]
+			if !ok {
+				complete = false
+				break
+			}
+			fmt.Fprintf(&b, "\x00%s=%v", name, value)
+		}
+		if complete {
+			keys = append(keys, b.String())
+		}
+	}
+	return keys, true
+}
+
+// primaryKeyFields - get the sorted primary key field names of the table from its schema, the
+// schema is fetched by one caller at a time, false if it can not be fetched
+func (p *writePipeline) primaryKeyFields(database, table string) ([]string, bool) {
+	tableKey := pipelineTableKey(database, table)
+	p.mu.Lock()
+	names, ok := p.pkFields[tableKey]
+	p.mu.Unlock()
+	if ok {
+		return names, true
+	}
+	p.fetchMu.Lock()
+	defer p.fetchMu.Unlock()
+	p.mu.Lock()
+	names, ok = p.pkFields[tableKey]
+	p.mu.Unlock()
+	if ok {
+		return names, true
+	}
+	description, err := p.cli.tableSchema(database, table)
+	if err != nil {
+		return nil, false
+	}
+	if description != nil && description.Schema != nil {
+		for _, field := range description.Schema.Fields {
+			if field.PrimaryKey {
+				names = append(names, field.FieldName)
+			}
+		}
+	}
+	sort.Strings(names)
+	p.mu.Lock()
+	p.pkFields[tableKey] = names
+	p.mu.Unlock()
+	return names, true
+}
+
+// invalidateTable - forget the primary key fields of the table, e.g. after it is dropped
+func (p *writePipeline) invalidateTable(database, table string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.pkFields, pipelineTableKey(database, table))
+}
+
+// invalidateDatabase - forget the primary key fields of the tables of the database
+func (p *writePipeline) invalidateDatabase(database string) {
+	prefix := pipelineTableKey(database, "")
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for tableKey := range p.pkFields {
+		if strings.HasPrefix(tableKey, prefix) {
+			delete(p.pkFields, tableKey)
+		}
+	}
+}
+
+func pipelineTableKey(database, table string) string {
+	return database + "\x00" + table
+}
+
+// flush - send the batch when the max delay is reached unless it has been sent for being full
+func (p *writePipeline) flush(key pipelineKey, batch *pipelineBatch) {
+	p.mu.Lock()
+	if p.pending[key] != batch {
+		p.mu.Unlock()
+		return
+	}
+	delete(p.pending, key)
+	p.mu.Unlock()
+	p.send(key, batch)
+}
+
+// send - upsert the rows of the batch in one request and set the results of the writes, the
+// writes are sent separately if the rows of the merged request are rejected by the server
+func (p *writePipeline) send(key pipelineKey, batch *pipelineBatch) {
+	defer close(batch.done)
+	if len(batch.writes) == 1 {
+		p.sendWrite(key, batch.writes[0])
+		return
+	}
+	rows := make([]api.Row, 0, batch.rows)
+	for _, write := range batch.writes {
+		rows = append(rows, write.rows...)
+	}
+	result, err := api.UpsertRow(p.cli, &api.UpsertRowArg{
+		Database:   key.database,
+		Table:      key.table,
+		Rows:       rows,
+		TTLSeconds: key.ttlSeconds,
+	})
+	if rowsRejected(err) {
+		p.sendSeparately(key, batch)
+		return
+	}
+	if err != nil {
+		for _, write := range batch.writes {
+			write.err = err
+		}
+		return
+	}
+	if result.AffectedCount != uint64(batch.rows) {
+		// the affected rows can not be attributed to the writes, the upserts are idempotent so
+		// that the writes are sent again separately for their own counts
+		p.sendSeparately(key, batch)
+		return
+	}
+	splitPipelineResult(result, batch)
+}
+
+// sendSeparately - upsert the rows of each write of the batch by its own request concurrently
+func (p *writePipeline) sendSeparately(key pipelineKey, batch *pipelineBatch) {
+	var wg sync.WaitGroup
+	for _, write := range batch.writes {
+		wg.Add(1)
+		go func(write *pipelineWrite) {
+			defer wg.Done()
+			p.sendWrite(key, write)
+		}(write)
+	}
+	wg.Wait()
+}
+
+func (p *writePipeline) sendWrite(key pipelineKey, write *pipelineWrite) {
+	write.result, write.err = api.UpsertRow(p.cli, &api.UpsertRowArg{
+		Database:   key.database,
+		Table:      key.table,
+		Rows:       write.rows,
+		TTLSeconds: key.ttlSeconds,
+	})
+}
+
+// rowsRejected returns true if the server rejects the rows of the request, e.g. an invalid or a
+// too large row, so that sending the rows of each caller separately may succeed. The throttling,
+// authentication and server errors are not, resending would only add to the load of the server.
+func rowsRejected(err error) bool {
+	var serviceErr *client.BceServiceError
+	if !errors.As(err, &serviceErr) {
+		return false
+	}
+	switch serviceErr.StatusCode {
+	case http.StatusBadRequest, http.StatusConflict, http.StatusRequestEntityTooLarge,
+		http.StatusUnprocessableEntity:
+		return true
+	}
+	return false
+}
+
+// splitPipelineResult - split the result of the merged request affecting all its rows into the
+// results of the writes
+func splitPipelineResult(result *api.UpsertRowResult, batch *pipelineBatch) {
+	offset := 0
+	for _, write := range batch.writes {
+		write.result = &api.UpsertRowResult{AffectedCount: uint64(len(write.rows))}
+		if len(result.PrimaryKeys) == batch.rows {
+			write.result.PrimaryKeys = result.PrimaryKeys[offset : offset+len(write.rows)]
+		}
+		offset += len(write.rows)
+	}
+}
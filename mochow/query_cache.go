@@ -0,0 +1,284 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// query_cache.go - the optional client side LRU cache for the QueryRow results
+
+package mochow
+
+import (
+	"container/list"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+const (
+	DefaultQueryCacheCapacity = 10000
+	DefaultQueryCacheTTL      = 10 * time.Second
+)
+
+// QueryCacheOptions defines the options of the client side QueryRow cache. The results are cached
+// by database, table, primary key and projections, and invalidated on the writes to the same key
+// made by this client, the results of the reads in flight during such a write are not cached. The
+// reads with STRONG consistency always bypass the cache.
+type QueryCacheOptions struct {
+	// Capacity is the max number of cached results, use DefaultQueryCacheCapacity if not positive
+	Capacity int
+	// TTL is the max time a result is cached, use DefaultQueryCacheTTL if not positive
+	TTL time.Duration
+}
+
+type queryCacheEntry struct {
+	key      string
+	rowKey   string
+	tableKey string
+	tableGen uint64
+	expireAt time.Time
+	row      api.Row
+}
+
+type queryCache struct {
+	capacity int
+	ttl      time.Duration
+
+	mu        sync.Mutex
+	lru       *list.List
+	items     map[string]*list.Element       // cache key -> entry
+	rows      map[string]map[string]struct{} // row key -> cache keys
+	tableGens map[string]uint64              // table key -> generation
+	pkFields  map[string][]string            // table key -> primary key field names
+	// writeGen is increased by each invalidation, the results of the reads started before the
+	// last invalidation of the table or the database are not cached
+	writeGen    uint64
+	tableGuards map[string]uint64 // table key -> writeGen of the last invalidation
+	dbGuards    map[string]uint64 // database -> writeGen of the last invalidation
+}
+
+func newQueryCache(options *QueryCacheOptions) *queryCache {
+	c := &queryCache{
+		capacity:    options.Capacity,
+		ttl:         options.TTL,
+		lru:         list.New(),
+		items:       make(map[string]*list.Element),
+		rows:        make(map[string]map[string]struct{}),
+		tableGens:   make(map[string]uint64),
+		pkFields:    make(map[string][]string),
+		tableGuards: make(map[string]uint64),
+		dbGuards:    make(map[string]uint64),
+	}
+	if c.capacity <= 0 {
+		c.capacity = DefaultQueryCacheCapacity
+	}
+	if c.ttl <= 0 {
+		c.ttl = DefaultQueryCacheTTL
+	}
+	return c
+}
+
+func cacheTableKey(database, table string) string {
+	return database + "\x00" + table
+}
+
+func cacheRowKey(tableKey string, primaryKey map[string]interface{}) string {
+	names := make([]string, 0, len(primaryKey))
+	for name := range primaryKey {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString(tableKey)
+	for _, name := range names {
+		fmt.Fprintf(&b, "\x00%s=%v", name, primaryKey[name])
+	}
+	return b.String()
+}
+
+func cacheQueryKey(rowKey string, args *api.QueryRowArgs) string {
+	return fmt.Sprintf("%s\x01%s\x01%s\x01%t", rowKey, strings.Join(args.Projections, ","),
+		strings.Join(args.ExcludeProjections, ","), args.RetrieveVector)
+}
+
+func copyRow(row api.Row) api.Row {
+	if row.Fields == nil {
+		return row
+	}
+	fields := make(map[string]interface{}, len(row.Fields))
+	for k, v := range row.Fields {
+		fields[k] = v
+	}
+	return api.Row{Fields: fields}
+}
+
+func cacheable(args *api.QueryRowArgs) bool {
+	return args != nil && len(args.PrimaryKey) != 0 && args.ReadConsistency != api.STRONG
+}
+
+func (c *queryCache) get(args *api.QueryRowArgs) (*api.QueryRowResult, bool) {
+	tableKey := cacheTableKey(args.Database, args.Table)
+	key := cacheQueryKey(cacheRowKey(tableKey, args.PrimaryKey), args)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*queryCacheEntry)
+	if time.Now().After(entry.expireAt) || entry.tableGen != c.tableGens[tableKey] {
+		c.removeElement(elem)
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return &api.QueryRowResult{Row: copyRow(entry.row)}, true
+}
+
+// generation - get the generation of the writes before sending a read, it is passed to put so that
+// the result is not cached if the rows are written while the read is in flight
+func (c *queryCache) generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.writeGen
+}
+
+func (c *queryCache) put(args *api.QueryRowArgs, result *api.QueryRowResult, gen uint64) {
+	tableKey := cacheTableKey(args.Database, args.Table)
+	rowKey := cacheRowKey(tableKey, args.PrimaryKey)
+	key := cacheQueryKey(rowKey, args)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tableGuards[tableKey] > gen || c.dbGuards[args.Database] > gen {
+		// the result may be older than the write invalidating the table
+		return
+	}
+	if _, ok := c.pkFields[tableKey]; !ok {
+		names := make([]string, 0, len(args.PrimaryKey))
+		for name := range args.PrimaryKey {
+			names = append(names, name)
+		}
+		c.pkFields[tableKey] = names
+	}
+	if elem, ok := c.items[key]; ok {
+		c.removeElement(elem)
+	}
+	entry := &queryCacheEntry{
+		key:      key,
+		rowKey:   rowKey,
+		tableKey: tableKey,
+		tableGen: c.tableGens[tableKey],
+		expireAt: time.Now().Add(c.ttl),
+		row:      copyRow(result.Row),
+	}
+	c.items[key] = c.lru.PushFront(entry)
+	if c.rows[rowKey] == nil {
+		c.rows[rowKey] = make(map[string]struct{})
+	}
+	c.rows[rowKey][key] = struct{}{}
+	for c.lru.Len() > c.capacity {
+		c.removeElement(c.lru.Back())
+	}
+}
+
+func (c *queryCache) removeElement(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*queryCacheEntry)
+	delete(c.items, entry.key)
+	if keys, ok := c.rows[entry.rowKey]; ok {
+		delete(keys, entry.key)
+		if len(keys) == 0 {
+			delete(c.rows, entry.rowKey)
+		}
+	}
+}
+
+// invalidateKey - drop the cached results of the row with the primary key
+func (c *queryCache) invalidateKey(database, table string, primaryKey map[string]interface{}) {
+	tableKey := cacheTableKey(database, table)
+	rowKey := cacheRowKey(tableKey, primaryKey)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.guardTable(tableKey)
+	for key := range c.rows[rowKey] {
+		if elem, ok := c.items[key]; ok {
+			c.removeElement(elem)
+		}
+	}
+}
+
+// invalidateRows - drop the cached results of the written rows, the primary key fields are
+// learned from the previous QueryRow calls of the table
+func (c *queryCache) invalidateRows(database, table string, rows []api.Row) {
+	tableKey := cacheTableKey(database, table)
+	c.mu.Lock()
+	c.guardTable(tableKey)
+	names, ok := c.pkFields[tableKey]
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+	for _, row := range rows {
+		primaryKey := make(map[string]interface{}, len(names))
+		for _, name := range names {
+			value, ok := row.Fields[name]
+			if !ok {
+				// the primary key can not be found, e.g. auto increment
+				c.invalidateTable(database, table)
+				return
+			}
+			primaryKey[name] = value
+		}
+		c.invalidateKey(database, table, primaryKey)
+	}
+}
+
+// invalidateTable - drop all the cached results of the table lazily
+func (c *queryCache) invalidateTable(database, table string) {
+	tableKey := cacheTableKey(database, table)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.guardTable(tableKey)
+	c.tableGens[tableKey]++
+}
+
+// invalidateDatabase - drop all the cached results and the learned primary key fields of the tables
+// of the database, e.g. after it is dropped
+func (c *queryCache) invalidateDatabase(database string) {
+	prefix := cacheTableKey(database, "")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writeGen++
+	c.dbGuards[database] = c.writeGen
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		if strings.HasPrefix(elem.Value.(*queryCacheEntry).tableKey, prefix) {
+			c.removeElement(elem)
+		}
+		elem = next
+	}
+	for tableKey := range c.pkFields {
+		if strings.HasPrefix(tableKey, prefix) {
+			delete(c.pkFields, tableKey)
+		}
+	}
+}
+
+// guardTable - keep the reads of the table in flight from caching their results, the caller holds
+// the lock
+func (c *queryCache) guardTable(tableKey string) {
+	c.writeGen++
+	c.tableGuards[tableKey] = c.writeGen
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// range_search.go - the helper to collect all the pages of the range search
+
+package mochow
+
+import (
+	"context"
+	"errors"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+// RangeSearchAll - search all the rows within the distance range page by page following the
+// NextMarker of the results
+//
+// PARAMS:
+//   - ctx: the context to cancel the requests
+//   - args: the range search args, its Limit is the page size and its Marker is the start of the
+//     rows
+//
+// RETURNS:
+//   - []api.RowResult: all the rows within the range with the distances
+//   - error: nil if ok otherwise the specific error
+func (c *Client) RangeSearchAll(ctx context.Context,
+	args *api.RangeSearchArgs) ([]api.RowResult, error) {
+	if args == nil {
+		return nil, errors.New("range search args should not be nil")
+	}
+	c = c.WithContext(ctx)
+	pageArgs := *args
+	var rows []api.RowResult
+	for {
+		result, err := c.RangeSearchRow(&pageArgs)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, result.Rows...)
+		if !result.IsTruncated || len(result.NextMarker) == 0 {
+			return rows, nil
+		}
+		pageArgs.Marker = result.NextMarker
+	}
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// read.go - the option-style variants of the simple read methods
+
+package mochow
+
+import (
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+// Query - query the row of the primary key, it is the same as QueryRow with the args built from
+// the options, e.g.
+//
+//	cli.Query(database, table, primaryKey, api.WithReadConsistency(api.STRONG))
+//
+// PARAMS:
+//   - database: the database name
+//   - table: the table name
+//   - primaryKey: the primary key of the row
+//   - opts: the read options, the defaults are described by api.ReadOptions
+//
+// RETURNS:
+//   - *api.QueryRowResult: the found row
+//   - error: nil if ok otherwise the specific error
+func (c *Client) Query(database, table string, primaryKey map[string]interface{},
+	opts ...api.ReadOption) (*api.QueryRowResult, error) {
+	options := api.NewReadOptions(opts...)
+	return c.QueryRow(&api.QueryRowArgs{
+		Database:        database,
+		Table:           table,
+		PrimaryKey:      primaryKey,
+		PartitionKey:    options.PartitionKey,
+		Projections:     options.Projections,
+		RetrieveVector:  options.RetrieveVector,
+		ReadConsistency: options.ReadConsistency,
+	})
+}
+
+// Select - select a page of the rows matching the filter, it is the same as SelectRow with the
+// args built from the options
+//
+// PARAMS:
+//   - database: the database name
+//   - table: the table name
+//   - filter: the filter of the rows, all the rows are selected if empty
+//   - limit: the max number of rows
+//   - opts: the read options, the partition key is ignored
+//
+// RETURNS:
+//   - *api.SelectRowResult: the selected rows and the marker of the next page
+//   - error: nil if ok otherwise the specific error
+func (c *Client) Select(database, table, filter string, limit uint64,
+	opts ...api.ReadOption) (*api.SelectRowResult, error) {
+	options := api.NewReadOptions(opts...)
+	return c.SelectRow(&api.SelectRowArgs{
+		Database:        database,
+		Table:           table,
+		Filter:          filter,
+		Limit:           limit,
+		Projections:     options.Projections,
+		RetrieveVector:  options.RetrieveVector,
+		ReadConsistency: options.ReadConsistency,
+	})
+}
+
+// Search - search the rows by the vector, it is the same as SearchRow with the args built from the
+// options
+//
+// PARAMS:
+//   - database: the database name
+//   - table: the table name
+//   - anns: the vector search params
+//   - opts: the read options
+//
+// RETURNS:
+//   - *api.SearchRowResult: the found rows with the distances
+//   - error: nil if ok otherwise the specific error
+func (c *Client) Search(database, table string, anns *api.ANNSearchParams,
+	opts ...api.ReadOption) (*api.SearchRowResult, error) {
+	options := api.NewReadOptions(opts...)
+	return c.SearchRow(&api.SearchRowArgs{
+		Database:        database,
+		Table:           table,
+		ANNS:            anns,
+		PartitionKey:    options.PartitionKey,
+		Projections:     options.Projections,
+		RetrieveVector:  options.RetrieveVector,
+		ReadConsistency: options.ReadConsistency,
+	})
+}
@@ -0,0 +1,163 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// reindex.go - the orchestrator to drop, create and rebuild an index
+
+package mochow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+const DefaultReindexPollInterval = 5 * time.Second
+
+// ReindexStage is the stage of the reindex reported to the progress callback
+type ReindexStage string
+
+const (
+	ReindexStageDropping    ReindexStage = "DROPPING"
+	ReindexStageCreating    ReindexStage = "CREATING"
+	ReindexStageRebuilding  ReindexStage = "REBUILDING"
+	ReindexStageDone        ReindexStage = "DONE"
+	ReindexStageRollingBack ReindexStage = "ROLLING_BACK"
+)
+
+// ReindexOptions defines the options of Reindex
+type ReindexOptions struct {
+	// PollInterval is the interval to check the state of the index, use
+	// DefaultReindexPollInterval if not positive
+	PollInterval time.Duration
+	// OnProgress is called when the reindex enters a stage
+	OnProgress func(stage ReindexStage)
+	// DisableRollback keeps the table without the index if the reindex fails after dropping the
+	// previous index, by default the previous index is recreated and rebuilt
+	DisableRollback bool
+}
+
+// Reindex - replace the index of the table with the new schema, it drops the previous index if
+// exists, creates the new index, rebuilds it if it is a vector index and waits until the state is
+// NORMAL. If any step fails after the previous index is dropped, the previous index is recreated
+// unless the rollback is disabled.
+//
+// PARAMS:
+//   - ctx: the context to cancel the requests and the waiting, the rollback is not affected by
+//     the cancellation
+//   - database: the database name
+//   - table: the table name
+//   - index: the schema of the new index, its IndexName is the index to replace
+//   - options: the options of the reindex, nil means default
+//
+// RETURNS:
+//   - error: nil if ok otherwise the specific error
+func (c *Client) Reindex(ctx context.Context, database, table string, index api.IndexSchema,
+	options *ReindexOptions) error {
+	if len(index.IndexName) == 0 {
+		return errors.New("index name should not be empty")
+	}
+	if options == nil {
+		options = &ReindexOptions{}
+	}
+	r := &reindexer{cli: c, database: database, table: table, options: *options}
+	if r.options.PollInterval <= 0 {
+		r.options.PollInterval = DefaultReindexPollInterval
+	}
+
+	previous, err := c.WithContext(ctx).DescIndex(database, table, index.IndexName)
+	if err != nil && !api.IsIndexNotExist(err) {
+		return err
+	}
+	if err == nil {
+		r.progress(ReindexStageDropping)
+		if err := r.drop(ctx, index.IndexName); err != nil {
+			return err
+		}
+	}
+
+	if err := r.build(ctx, index); err != nil {
+		if previous == nil || r.options.DisableRollback {
+			return err
+		}
+		r.progress(ReindexStageRollingBack)
+		if rollbackErr := r.rollback(previous.Index); rollbackErr != nil {
+			return fmt.Errorf("reindex failed: %w, rollback failed: %v", err, rollbackErr)
+		}
+		return fmt.Errorf("reindex failed and rolled back: %w", err)
+	}
+	r.progress(ReindexStageDone)
+	return nil
+}
+
+type reindexer struct {
+	cli      *Client
+	database string
+	table    string
+	options  ReindexOptions
+}
+
+func (r *reindexer) progress(stage ReindexStage) {
+	if r.options.OnProgress != nil {
+		r.options.OnProgress(stage)
+	}
+}
+
+// drop - drop the index and wait until it does not exist
+func (r *reindexer) drop(ctx context.Context, indexName string) error {
+	cli := r.cli.WithContext(ctx)
+	if err := cli.DropIndex(r.database, r.table, indexName); err != nil {
+		return err
+	}
+	return cli.WaitForIndexDropped(ctx, r.database, r.table, indexName,
+		&WaitOptions{PollInterval: r.options.PollInterval})
+}
+
+// build - create the index, rebuild it if it is a vector index and wait until it is NORMAL
+func (r *reindexer) build(ctx context.Context, index api.IndexSchema) error {
+	cli := r.cli.WithContext(ctx)
+	r.progress(ReindexStageCreating)
+	index.State = ""
+	err := cli.CreateIndex(&api.CreateIndexArgs{
+		Database: r.database,
+		Table:    r.table,
+		Indexes:  []api.IndexSchema{index},
+	})
+	if err != nil {
+		return err
+	}
+	if index.IndexType != api.SecondaryIndex {
+		r.progress(ReindexStageRebuilding)
+		if err := cli.RebuildIndex(r.database, r.table, index.IndexName); err != nil {
+			return err
+		}
+	}
+	return cli.WaitForIndexReady(ctx, r.database, r.table, index.IndexName,
+		&WaitOptions{PollInterval: r.options.PollInterval})
+}
+
+// rollback - drop the new index if created and recreate the previous index
+func (r *reindexer) rollback(previous api.IndexSchema) error {
+	ctx := context.Background()
+	_, err := r.cli.DescIndex(r.database, r.table, previous.IndexName)
+	if err == nil {
+		err = r.drop(ctx, previous.IndexName)
+	}
+	if err != nil && !api.IsIndexNotExist(err) {
+		return err
+	}
+	return r.build(ctx, previous)
+}
@@ -0,0 +1,224 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// rerank.go - the client side fusion of the search results
+
+// Package rerank merges the results of multiple searches on the client side, e.g. the searches of
+// different vector fields or tables when the server can not fuse them. The RRFRanker fuses the
+// lists by the ranks of the rows, and the WeightedRanker by the weighted sum of the distances
+// normalized by the metric types.
+package rerank
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+// DefaultRRFK is the rank constant of the RRF fusion
+const DefaultRRFK = 60
+
+// Input is a result list to merge, the rows are in the order of the search, i.e. the nearest
+// first. Metric is the metric type of the searched vector index, it is required by the
+// WeightedRanker only.
+type Input struct {
+	Result *api.SearchRowResult
+	Metric api.MetricType
+}
+
+// Ranker merges the result lists into one list in the descending order of the fused scores
+type Ranker interface {
+	Rerank(inputs []Input) (*api.SearchRowResult, error)
+}
+
+// RRFRanker fuses the lists by the reciprocal rank fusion, the score of a row is the sum of
+// 1 / (K + rank) of the lists containing it with the ranks starting from 1
+type RRFRanker struct {
+	// K is the rank constant, use DefaultRRFK if zero
+	K uint32
+	// KeyFields are the fields identifying the same row in the lists, e.g. the primary key
+	KeyFields []string
+	// Limit caps the number of merged rows, no limit if not positive
+	Limit int
+}
+
+// Rerank - merge the lists by the RRF fusion
+//
+// PARAMS:
+//   - inputs: the result lists, the nil results are skipped
+//
+// RETURNS:
+//   - *api.SearchRowResult: the merged rows, the Distance of each row is the fused score in
+//     descending order
+//   - error: nil if ok otherwise the missing key fields
+func (r *RRFRanker) Rerank(inputs []Input) (*api.SearchRowResult, error) {
+	k := float64(r.K)
+	if r.K == 0 {
+		k = DefaultRRFK
+	}
+	return merge(inputs, r.KeyFields, r.Limit, func(int, Input) (scoreFunc, error) {
+		return func(rank int, _ float64) float64 {
+			return 1 / (k + float64(rank+1))
+		}, nil
+	})
+}
+
+// WeightedRanker fuses the lists by the weighted sum of the similarities, the distances of each
+// list are normalized to the similarities in [0, 1] by its metric type:
+//   - L2 and HAMMING: 1 / (1 + distance)
+//   - IP: 0.5 + atan(distance) / π, the inner product is larger if nearer
+//   - COSINE: (1 + distance) / 2, the cosine similarity is in [-1, 1]
+//   - JACCARD: 1 - distance
+type WeightedRanker struct {
+	// Weights are the weights of the lists in turn, all the lists have the same weight if empty
+	Weights []float64
+	// KeyFields are the fields identifying the same row in the lists, e.g. the primary key
+	KeyFields []string
+	// Limit caps the number of merged rows, no limit if not positive
+	Limit int
+}
+
+// Rerank - merge the lists by the weighted sum of the normalized distances
+//
+// PARAMS:
+//   - inputs: the result lists with the metric types, the nil results are skipped
+//
+// RETURNS:
+//   - *api.SearchRowResult: the merged rows, the Distance of each row is the fused score in
+//     descending order
+//   - error: nil if ok otherwise the invalid weights, metric types or missing key fields
+func (r *WeightedRanker) Rerank(inputs []Input) (*api.SearchRowResult, error) {
+	weights := r.Weights
+	if len(weights) == 0 {
+		weights = make([]float64, len(inputs))
+		for i := range weights {
+			weights[i] = 1
+		}
+	}
+	if len(weights) != len(inputs) {
+		return nil, fmt.Errorf("%d weights do not match %d inputs", len(weights), len(inputs))
+	}
+	var total float64
+	for _, weight := range weights {
+		if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return nil, fmt.Errorf("weight %v should be non-negative", weight)
+		}
+		total += weight
+	}
+	if len(inputs) != 0 && total == 0 {
+		return nil, errors.New("weights should not be all zero")
+	}
+	return merge(inputs, r.KeyFields, r.Limit, func(i int, input Input) (scoreFunc, error) {
+		normalize, err := normalizer(input.Metric)
+		if err != nil {
+			return nil, err
+		}
+		weight := weights[i]
+		return func(_ int, distance float64) float64 {
+			return weight * normalize(distance)
+		}, nil
+	})
+}
+
+// normalizer - get the function converting the distances of the metric type to the similarities
+func normalizer(metric api.MetricType) (func(float64) float64, error) {
+	switch metric {
+	case api.L2, api.HAMMING:
+		return func(d float64) float64 { return 1 / (1 + d) }, nil
+	case api.IP:
+		return func(d float64) float64 { return 0.5 + math.Atan(d)/math.Pi }, nil
+	case api.COSINE:
+		return func(d float64) float64 { return (1 + d) / 2 }, nil
+	case api.JACCARD:
+		return func(d float64) float64 { return 1 - d }, nil
+	}
+	return nil, fmt.Errorf("unsupported metric type %q", metric)
+}
+
+// scoreFunc scores a row of a list by its rank starting from 0 and its distance
+type scoreFunc func(rank int, distance float64) float64
+
+type scoredRow struct {
+	row   api.Row
+	score float64
+}
+
+// merge - sum the scores of the rows of the same keys, the scorer of each list scores its rows by
+// their ranks and distances, and the fields of the same row in the lists are merged
+func merge(inputs []Input, keyFields []string, limit int,
+	scorer func(int, Input) (scoreFunc, error)) (*api.SearchRowResult, error) {
+	if len(keyFields) == 0 {
+		return nil, errors.New("key fields should not be empty")
+	}
+	var merged []*scoredRow
+	byKey := make(map[string]*scoredRow)
+	for i, input := range inputs {
+		if input.Result == nil {
+			continue
+		}
+		score, err := scorer(i, input)
+		if err != nil {
+			return nil, fmt.Errorf("invalid input %d: %w", i, err)
+		}
+		for rank, result := range input.Result.Rows {
+			key, err := rowKey(result.Row, keyFields)
+			if err != nil {
+				return nil, fmt.Errorf("invalid row %d of input %d: %w", rank, i, err)
+			}
+			s, ok := byKey[key]
+			if !ok {
+				fields := make(map[string]interface{}, len(result.Row.Fields))
+				s = &scoredRow{row: api.Row{Fields: fields}}
+				byKey[key] = s
+				merged = append(merged, s)
+			}
+			for name, value := range result.Row.Fields {
+				if _, ok := s.row.Fields[name]; !ok {
+					s.row.Fields[name] = value
+				}
+			}
+			s.score += score(rank, result.Distance)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].score > merged[j].score
+	})
+	if limit > 0 && len(merged) > limit {
+		merged = merged[:limit]
+	}
+	result := &api.SearchRowResult{Rows: make([]api.RowResult, len(merged))}
+	for i, s := range merged {
+		result.Rows[i] = api.RowResult{Row: s.row, Distance: s.score}
+	}
+	return result, nil
+}
+
+func rowKey(row api.Row, keyFields []string) (string, error) {
+	var b strings.Builder
+	for i, field := range keyFields {
+		value, ok := row.Fields[field]
+		if !ok {
+			return "", fmt.Errorf("key field %s not found", field)
+		}
+		if i > 0 {
+			b.WriteByte(0)
+		}
+		fmt.Fprint(&b, value)
+	}
+	return b.String(), nil
+}
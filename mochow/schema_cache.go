@@ -0,0 +1,178 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// schema_cache.go - the optional client side cache of the table schemas to check the writes
+
+package mochow
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+const DefaultSchemaCacheTTL = 5 * time.Minute
+
+// SchemaCacheOptions defines the options of the client side cache of the DescTable results. The
+// cached schemas are used to check the rows of InsertRow and UpsertRow before sending, e.g. the
+// unknown fields and the vectors not matching the dimensions. The schema of a table is
+// invalidated on the schema changes made by this client, and by InvalidateSchema for the
+// changes made by others.
+type SchemaCacheOptions struct {
+	// TTL is the max time a schema is cached, use DefaultSchemaCacheTTL if not positive
+	TTL time.Duration
+}
+
+type schemaEntry struct {
+	table    *api.TableDescription
+	expireAt time.Time
+}
+
+type schemaCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]schemaEntry // table key -> description
+}
+
+func newSchemaCache(options *SchemaCacheOptions) *schemaCache {
+	c := &schemaCache{
+		ttl:     options.TTL,
+		entries: make(map[string]schemaEntry),
+	}
+	if c.ttl <= 0 {
+		c.ttl = DefaultSchemaCacheTTL
+	}
+	return c
+}
+
+func (c *schemaCache) get(database, table string) (*api.TableDescription, bool) {
+	if c == nil {
+		return nil, false
+	}
+	key := cacheTableKey(database, table)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expireAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.table, true
+}
+
+func (c *schemaCache) put(database, table string, description *api.TableDescription) {
+	if c == nil || description == nil || description.Schema == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[cacheTableKey(database, table)] = schemaEntry{
+		table:    description,
+		expireAt: time.Now().Add(c.ttl),
+	}
+}
+
+func (c *schemaCache) invalidate(database, table string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, cacheTableKey(database, table))
+}
+
+func (c *schemaCache) invalidateDatabase(database string) {
+	if c == nil {
+		return
+	}
+	prefix := cacheTableKey(database, "")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// InvalidateSchema - drop the cached schema of the table, e.g. after the table is altered by
+// another client, the next write fetches the schema again
+func (c *Client) InvalidateSchema(database, table string) {
+	if table, ok := c.aliases.get(database, table); ok {
+		c.schemaCache.invalidate(database, table)
+	}
+	c.schemaCache.invalidate(database, table)
+}
+
+// tableSchema - get the description of the resolved table from the schema cache if cached,
+// otherwise by DescTable
+func (c *Client) tableSchema(database, table string) (*api.TableDescription, error) {
+	if description, ok := c.schemaCache.get(database, table); ok {
+		return description, nil
+	}
+	result, err := api.DescTable(c, &api.DescTableArgs{Database: database, Table: table})
+	if err != nil {
+		return nil, err
+	}
+	c.schemaCache.put(database, table, result.Table)
+	return result.Table, nil
+}
+
+// checkCachedRows - check the rows against the cached schema of the resolved table if the schema
+// cache is enabled
+func (c *Client) checkCachedRows(database, table string, rows []api.Row) error {
+	if c.schemaCache == nil || c.disableArgsValidation {
+		return nil
+	}
+	description, err := c.tableSchema(database, table)
+	if err != nil {
+		return err
+	}
+	return checkRows(description, rows)
+}
+
+// DecodeRowFields - convert the transported values of the rows read from the table to the go
+// types by the schema of the table, i.e. the DATE, DATETIME and TIMESTAMP fields to time.Time and
+// the BINARY fields to []byte. The schema is cached if the schema cache is enabled.
+//
+// PARAMS:
+//   - database: the database name
+//   - table: the table name
+//   - rows: the rows to convert in place
+//
+// RETURNS:
+//   - error: nil if ok otherwise the specific error
+func (c *Client) DecodeRowFields(database, table string, rows []api.Row) error {
+	table, err := c.resolveTable(database, table)
+	if err != nil {
+		return err
+	}
+	description, err := c.tableSchema(database, table)
+	if err != nil || description == nil {
+		return err
+	}
+	for i := range rows {
+		if err := rows[i].DecodeFields(description.Schema); err != nil {
+			return fmt.Errorf("decode row %d failed: %w", i, err)
+		}
+	}
+	return nil
+}
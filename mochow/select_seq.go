@@ -0,0 +1,61 @@
+//go:build go1.23
+
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// select_seq.go - the range-over-func iterator of the selected rows
+
+package mochow
+
+import (
+	"iter"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+// SelectRowSeq - select the rows page by page following the NextMarker of the results, the
+// iteration yields each row and stops after yielding the error if any request fails. The usage is:
+//
+//	for row, err := range cli.SelectRowSeq(args) {
+//		if err != nil {
+//			break
+//		}
+//	}
+//
+// PARAMS:
+//   - args: the select args, its Limit is the page size and its Marker is the start of the rows
+//
+// RETURNS:
+//   - iter.Seq2[api.Row, error]: the iterator of the rows
+func (c *Client) SelectRowSeq(args *api.SelectRowArgs) iter.Seq2[api.Row, error] {
+	return func(yield func(api.Row, error) bool) {
+		pageArgs := *args
+		for {
+			result, err := c.SelectRow(&pageArgs)
+			if err != nil {
+				yield(api.Row{}, err)
+				return
+			}
+			for _, row := range result.Rows {
+				if !yield(row, nil) {
+					return
+				}
+			}
+			if !result.IsTruncated || len(result.NextMarker) == 0 {
+				return
+			}
+			pageArgs.Marker = result.NextMarker
+		}
+	}
+}
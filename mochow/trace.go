@@ -0,0 +1,85 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// trace.go - the propagation of the tracing headers and the response ids via the context
+
+package mochow
+
+import (
+	"context"
+	"strings"
+
+	"github.com/baidu/mochow-sdk-go/client"
+)
+
+// TraceHeaders are the W3C trace context and baggage headers propagated from the context
+var TraceHeaders = []string{"traceparent", "tracestate", "baggage"}
+
+type traceHeadersKey struct{}
+
+type responseMetadataKey struct{}
+
+// ContextWithTraceHeaders - return a copy of ctx carrying the trace headers, e.g. injected by the
+// propagator of the tracer of the caller. The header names are case insensitive and the headers
+// other than TraceHeaders are ignored.
+func ContextWithTraceHeaders(ctx context.Context, headers map[string]string) context.Context {
+	trace := make(map[string]string, len(TraceHeaders))
+	for key, value := range headers {
+		for _, name := range TraceHeaders {
+			if strings.EqualFold(key, name) && len(value) != 0 {
+				trace[name] = value
+			}
+		}
+	}
+	return context.WithValue(ctx, traceHeadersKey{}, trace)
+}
+
+// TraceHeadersFromContext - get the trace headers carried by ctx, nil if none
+func TraceHeadersFromContext(ctx context.Context) map[string]string {
+	trace, _ := ctx.Value(traceHeadersKey{}).(map[string]string)
+	return trace
+}
+
+// ContextWithResponseMetadata - return a copy of ctx carrying the metadata to be filled with the
+// request id and debug id of the responses, so that the ids can be stitched into the trace of the
+// caller
+func ContextWithResponseMetadata(ctx context.Context,
+	metadata *client.ResponseMetadata) context.Context {
+	return context.WithValue(ctx, responseMetadataKey{}, metadata)
+}
+
+// ResponseMetadataFromContext - get the response metadata carried by ctx, nil if none
+func ResponseMetadataFromContext(ctx context.Context) *client.ResponseMetadata {
+	metadata, _ := ctx.Value(responseMetadataKey{}).(*client.ResponseMetadata)
+	return metadata
+}
+
+// WithTrace - derive a client which propagates the trace headers carried by ctx onto its requests
+// and fills the response metadata carried by ctx if any
+//
+// PARAMS:
+//   - ctx: the context of the caller
+//
+// RETURNS:
+//   - *Client: the derived client sharing the connections and the caches with c
+func (c *Client) WithTrace(ctx context.Context) *Client {
+	derived := c
+	if trace := TraceHeadersFromContext(ctx); len(trace) != 0 {
+		derived = derived.WithHeaders(trace)
+	}
+	if metadata := ResponseMetadataFromContext(ctx); metadata != nil {
+		derived = derived.WithResponseMetadata(metadata)
+	}
+	return derived
+}
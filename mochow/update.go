@@ -0,0 +1,96 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// update.go - the partial updates of the scalar and vector fields of a row
+
+package mochow
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+// UpdateVectorArgs defines the args of UpdateVectorOnly
+type UpdateVectorArgs struct {
+	Database     string
+	Table        string
+	PrimaryKey   map[string]interface{}
+	PartitionKey map[string]interface{}
+	// Field is the name of the vector field
+	Field  string
+	Vector []float32
+}
+
+// UpdateVectorOnly - replace the vector of a row, the scalar fields are neither sent nor changed
+//
+// PARAMS:
+//   - args: the args to update the vector
+//
+// RETURNS:
+//   - error: nil if ok otherwise the specific error
+func (c *Client) UpdateVectorOnly(args *UpdateVectorArgs) error {
+	if args == nil {
+		return errors.New("update vector args should not be nil")
+	}
+	if len(args.Field) == 0 || len(args.Vector) == 0 {
+		return errors.New("vector field and vector should not be empty")
+	}
+	return c.UpdateRow(&api.UpdateRowArgs{
+		Database:     args.Database,
+		Table:        args.Table,
+		PrimaryKey:   args.PrimaryKey,
+		PartitionKey: args.PartitionKey,
+		Update:       map[string]interface{}{args.Field: args.Vector},
+	})
+}
+
+// UpdateScalars - update the scalar fields of a row without re-sending the vector, the vector
+// fields are kept as is. The values of the vector fields of the table schema, i.e. FLOAT_VECTOR
+// and BINARY_VECTOR, are rejected whatever their Go types are, so that the metadata edits do not
+// send the huge vectors by mistake. The schema is fetched by DescTable if the schema cache is
+// disabled. UpsertRow replaces the whole rows and always sends the vectors, use UpdateScalars and
+// UpdateVectorOnly to change either part of the existing rows.
+//
+// PARAMS:
+//   - args: the args to update the row, the values of Update should be scalars
+//
+// RETURNS:
+//   - error: nil if ok otherwise the specific error
+func (c *Client) UpdateScalars(args *api.UpdateRowArgs) error {
+	if args == nil {
+		return errors.New("update row args should not be nil")
+	}
+	if err := c.resolveArgs(&args); err != nil {
+		return err
+	}
+	description, err := c.tableSchema(args.Database, args.Table)
+	if err != nil {
+		return err
+	}
+	if description != nil && description.Schema != nil {
+		for _, field := range description.Schema.Fields {
+			if _, ok := args.Update[field.FieldName]; !ok {
+				continue
+			}
+			switch field.FieldType {
+			case api.FieldTypeFloatVector, api.FieldTypeBinaryVector:
+				return fmt.Errorf("field %s is a vector, use UpdateVectorOnly instead",
+					field.FieldName)
+			}
+		}
+	}
+	return c.UpdateRow(args)
+}
@@ -0,0 +1,391 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// validate.go - the client side validation of the names, schemas and rows before sending
+
+package mochow
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/baidu/mochow-sdk-go/client"
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+	"github.com/baidu/mochow-sdk-go/mochow/filter"
+)
+
+const MaxNameLength = 255
+
+// InvalidNameError reports the invalid name of a database, table, alias, field or index
+type InvalidNameError struct {
+	// Kind is the kind of the entity, e.g. "table"
+	Kind   string
+	Name   string
+	Reason string
+}
+
+func (e *InvalidNameError) Error() string {
+	return fmt.Sprintf("invalid %s name %q: %s", e.Kind, e.Name, e.Reason)
+}
+
+// ValidateName - check the name of an entity, the name should start with a letter and only contain
+// letters, digits and underscores, and its length should be in [1, MaxNameLength]
+//
+// PARAMS:
+//   - kind: the kind of the entity reported in the error, e.g. "table"
+//   - name: the name to check
+//
+// RETURNS:
+//   - error: *InvalidNameError if invalid otherwise nil
+func ValidateName(kind, name string) error {
+	if len(name) == 0 {
+		return &InvalidNameError{Kind: kind, Name: name, Reason: "should not be empty"}
+	}
+	if len(name) > MaxNameLength {
+		return &InvalidNameError{Kind: kind, Name: name,
+			Reason: fmt.Sprintf("length %d exceeds %d", len(name), MaxNameLength)}
+	}
+	for i := 0; i < len(name); i++ {
+		b := name[i]
+		switch {
+		case b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z':
+		case i > 0 && (b >= '0' && b <= '9' || b == '_'):
+		case i == 0:
+			return &InvalidNameError{Kind: kind, Name: name, Reason: "should start with a letter"}
+		default:
+			return &InvalidNameError{Kind: kind, Name: name,
+				Reason: fmt.Sprintf("character %q at %d is not a letter, digit or underscore", b, i)}
+		}
+	}
+	return nil
+}
+
+func validateSchema(schema *api.TableSchema) error {
+	if schema == nil {
+		return nil
+	}
+	for _, field := range schema.Fields {
+		if err := ValidateName("field", field.FieldName); err != nil {
+			return err
+		}
+	}
+	for _, index := range schema.Indexes {
+		if err := validateIndex(index); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateIndex(index api.IndexSchema) error {
+	if err := ValidateName("index", index.IndexName); err != nil {
+		return err
+	}
+	for _, field := range index.IndexedFields() {
+		if err := ValidateName("field", field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateCreateTableArgs(args *api.CreateTableArgs) error {
+	if err := ValidateName("database", args.Database); err != nil {
+		return err
+	}
+	if err := ValidateName("table", args.Table); err != nil {
+		return err
+	}
+	return validateSchema(args.Schema)
+}
+
+func invalidArgs(format string, args ...interface{}) error {
+	return client.NewBceClientError(fmt.Sprintf(format, args...))
+}
+
+func isVectorField(fieldType api.FieldType) bool {
+	return fieldType == api.FieldTypeFloatVector || fieldType == api.FieldTypeBinaryVector
+}
+
+func isVectorIndex(indexType api.IndexType) bool {
+	switch indexType {
+	case api.HNSW, api.FLAT, api.PUCK, api.HNSWPQ, api.BinaryFlat, api.BinaryHNSW:
+		return true
+	}
+	return false
+}
+
+func isTextField(fieldType api.FieldType) bool {
+	switch fieldType {
+	case api.FieldTypeText, api.FieldTypeTextGBK, api.FieldTypeTextGB18030:
+		return true
+	}
+	return false
+}
+
+// checkTableArgs - check the definitions of the schema of the created table, i.e. exactly one
+// primary key and one partition key, the positive dimensions of the vector fields, the partitions
+// and the fields of the indexes
+func checkTableArgs(args *api.CreateTableArgs) error {
+	if args == nil {
+		return invalidArgs("create table args should not be nil")
+	}
+	if args.Schema == nil || len(args.Schema.Fields) == 0 {
+		return invalidArgs("fields of table %s should not be empty", args.Table)
+	}
+	fields := make(map[string]api.FieldSchema, len(args.Schema.Fields))
+	var primaryKeys, partitionKeys int
+	for _, field := range args.Schema.Fields {
+		if _, ok := fields[field.FieldName]; ok {
+			return invalidArgs("field %s is duplicated", field.FieldName)
+		}
+		fields[field.FieldName] = field
+		if len(field.FieldType) == 0 {
+			return invalidArgs("type of field %s should not be empty", field.FieldName)
+		}
+		if isVectorField(field.FieldType) && field.Dimension == 0 {
+			return invalidArgs("dimension of vector field %s should be positive", field.FieldName)
+		}
+		if field.PrimaryKey {
+			primaryKeys++
+		}
+		if field.PartitionKey {
+			partitionKeys++
+		}
+	}
+	if primaryKeys != 1 {
+		return invalidArgs("table %s should have exactly one primary key, got %d",
+			args.Table, primaryKeys)
+	}
+	if partitionKeys != 1 {
+		return invalidArgs("table %s should have exactly one partition key, got %d",
+			args.Table, partitionKeys)
+	}
+	if args.Partition != nil {
+		var keyType api.FieldType
+		for _, field := range args.Schema.Fields {
+			if field.PartitionKey {
+				keyType = field.FieldType
+			}
+		}
+		if err := args.Partition.ValidateFor(keyType); err != nil {
+			return invalidArgs("invalid partition of table %s: %v", args.Table, err)
+		}
+	}
+	if len(args.TTLField) != 0 {
+		field, ok := fields[args.TTLField]
+		if !ok {
+			return invalidArgs("ttl field %s of table %s not found", args.TTLField, args.Table)
+		}
+		if field.FieldType != api.FieldTypeDatetime && field.FieldType != api.FieldTypeTimestamp {
+			return invalidArgs("ttl field %s should be DATETIME or TIMESTAMP, got %s",
+				field.FieldName, field.FieldType)
+		}
+		if args.TTLSeconds == 0 {
+			return invalidArgs("ttl seconds of table %s should be positive with the ttl field",
+				args.Table)
+		}
+	}
+	for _, index := range args.Schema.Indexes {
+		if err := checkIndex(index); err != nil {
+			return err
+		}
+		for _, name := range index.IndexedFields() {
+			field, ok := fields[name]
+			if !ok {
+				return invalidArgs("field %s of index %s not found", name, index.IndexName)
+			}
+			if isVectorIndex(index.IndexType) != isVectorField(field.FieldType) ||
+				(index.IndexType == api.InvertedIndex && !isTextField(field.FieldType)) {
+				return invalidArgs("index %s of type %s can not be built on the %s field %s",
+					index.IndexName, index.IndexType, field.FieldType, field.FieldName)
+			}
+		}
+	}
+	return nil
+}
+
+// checkIndex - check the required definitions of the index
+func checkIndex(index api.IndexSchema) error {
+	if len(index.IndexType) == 0 {
+		return invalidArgs("type of index %s should not be empty", index.IndexName)
+	}
+	if len(index.Field) == 0 && len(index.Fields) == 0 {
+		return invalidArgs("field of index %s should not be empty", index.IndexName)
+	}
+	if len(index.Field) != 0 && len(index.Fields) != 0 {
+		return invalidArgs("only one of field and fields of index %s should be set",
+			index.IndexName)
+	}
+	if len(index.Fields) != 0 && index.IndexType != api.SecondaryIndex {
+		return invalidArgs("index %s of type %s can not be built on multiple fields",
+			index.IndexName, index.IndexType)
+	}
+	seen := make(map[string]bool, len(index.Fields))
+	for _, field := range index.Fields {
+		if seen[field] {
+			return invalidArgs("field %s of index %s is duplicated", field, index.IndexName)
+		}
+		seen[field] = true
+	}
+	if isVectorIndex(index.IndexType) && len(index.MetricType) == 0 {
+		return invalidArgs("metric type of vector index %s should not be empty", index.IndexName)
+	}
+	return nil
+}
+
+func checkIndexArgs(args *api.CreateIndexArgs) error {
+	if args == nil {
+		return invalidArgs("create index args should not be nil")
+	}
+	if len(args.Indexes) == 0 {
+		return invalidArgs("indexes should not be empty")
+	}
+	for _, index := range args.Indexes {
+		if err := checkIndex(index); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkWriteArgs - check the target and the rows of the insert or upsert
+func checkWriteArgs(database, table string, rows []api.Row) error {
+	if len(database) == 0 || len(table) == 0 {
+		return invalidArgs("database and table should not be empty")
+	}
+	if len(rows) == 0 {
+		return invalidArgs("rows should not be empty")
+	}
+	for i := range rows {
+		if len(rows[i].Fields) == 0 {
+			return invalidArgs("fields of row %d should not be empty", i)
+		}
+	}
+	return nil
+}
+
+// checkRows - check the fields of the rows against the schema of the table, i.e. the unknown
+// fields, the missing primary keys and the shapes of the vectors
+func checkRows(table *api.TableDescription, rows []api.Row) error {
+	if table == nil || table.Schema == nil {
+		return nil
+	}
+	fields := make(map[string]api.FieldSchema, len(table.Schema.Fields))
+	for _, field := range table.Schema.Fields {
+		fields[field.FieldName] = field
+	}
+	for i := range rows {
+		for _, field := range table.Schema.Fields {
+			if !field.PrimaryKey || field.AutoIncrement {
+				continue
+			}
+			if _, ok := rows[i].Fields[field.FieldName]; !ok {
+				return invalidArgs("primary key %s of row %d is missing", field.FieldName, i)
+			}
+		}
+		for name, value := range rows[i].Fields {
+			field, ok := fields[name]
+			if !ok {
+				if table.EnableDynamicField {
+					continue
+				}
+				return invalidArgs("unknown field %s of row %d", name, i)
+			}
+			if !isVectorField(field.FieldType) || value == nil {
+				continue
+			}
+			if err := checkVector(field, value); err != nil {
+				return invalidArgs("field %s of row %d: %s", name, i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkVector - check the length of the vector against the dimension of the field, the binary
+// vectors are packed into Dimension/8 bytes
+func checkVector(field api.FieldSchema, value interface{}) error {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return fmt.Errorf("expect a vector but got %T", value)
+	}
+	dimension := int(field.Dimension)
+	if field.FieldType == api.FieldTypeBinaryVector {
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("expect the packed bytes of the binary vector but got %T", value)
+		}
+		dimension = (dimension + 7) / 8
+	}
+	if v.Len() != dimension {
+		return fmt.Errorf("vector length %d does not match the dimension %d", v.Len(),
+			field.Dimension)
+	}
+	return nil
+}
+
+// ValidateRows - check the fields of the rows against the schema of the table, e.g. the unknown
+// fields and the vectors not matching the dimensions, so that a malformed row does not fail the
+// whole batch on the server. The schema is cached if the schema cache is enabled.
+//
+// PARAMS:
+//   - database: the database name
+//   - table: the table name
+//   - rows: the rows to write
+//
+// RETURNS:
+//   - error: *client.BceClientError if invalid, otherwise nil if ok or the error of DescTable
+func (c *Client) ValidateRows(database, table string, rows []api.Row) error {
+	if err := checkWriteArgs(database, table, rows); err != nil {
+		return err
+	}
+	table, err := c.resolveTable(database, table)
+	if err != nil {
+		return err
+	}
+	description, err := c.tableSchema(database, table)
+	if err != nil {
+		return err
+	}
+	return checkRows(description, rows)
+}
+
+// validateFilter - check the syntax of the filter if the filter validation is enabled
+func (c *Client) validateFilter(expr string) error {
+	if !c.validateFilters || len(expr) == 0 {
+		return nil
+	}
+	return filter.Validate(expr)
+}
+
+// ValidateFilter - check the syntax of the filter and the fields against the schema of the table,
+// e.g. the field names and the types of the compared values
+//
+// PARAMS:
+//   - database: the database name
+//   - table: the table name
+//   - expr: the filter expression
+//
+// RETURNS:
+//   - error: *filter.Error if invalid, otherwise nil if ok or the error of DescTable
+func (c *Client) ValidateFilter(database, table, expr string) error {
+	if err := filter.Validate(expr); err != nil {
+		return err
+	}
+	result, err := c.DescTable(database, table)
+	if err != nil {
+		return err
+	}
+	return filter.ValidateForTable(expr, result.Table)
+}
@@ -0,0 +1,200 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// verify.go - the verifier comparing the rows of the source and destination tables of a copy
+
+package mochow
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+const DefaultVerifyMaxMismatches = 100
+
+// TableRef refers to a table of a client, e.g. the source or destination of a copy
+type TableRef struct {
+	Client   *Client
+	Database string
+	Table    string
+}
+
+// VerifyOptions defines the options of VerifyCopy
+type VerifyOptions struct {
+	// SampleRate is the ratio of the source rows compared, the rows are sampled by the hash of
+	// the primary key so that the result is reproducible, all the rows are compared if it is not
+	// in (0, 1)
+	SampleRate float64
+	// Filter limits the source rows compared
+	Filter string
+	// MaxMismatches stops the comparison once reached, use DefaultVerifyMaxMismatches if not
+	// positive
+	MaxMismatches int
+	// BatchSize is the page size to export the source rows
+	BatchSize uint64
+}
+
+// MismatchReason is the reason of a mismatched row
+type MismatchReason string
+
+const (
+	MismatchMissing   MismatchReason = "MISSING"
+	MismatchDifferent MismatchReason = "DIFFERENT"
+)
+
+// Mismatch is a source row which is missing or different in the destination
+type Mismatch struct {
+	PrimaryKey map[string]interface{}
+	Reason     MismatchReason
+}
+
+// VerifyResult is the result of VerifyCopy
+type VerifyResult struct {
+	SourceRowCount      uint64
+	DestinationRowCount uint64
+	// ComparedRows is the number of the source rows compared with the destination
+	ComparedRows uint64
+	Mismatches   []Mismatch
+	// Truncated is true if the comparison stopped for reaching MaxMismatches
+	Truncated bool
+}
+
+// OK returns whether the row counts match and no mismatched row is found
+func (r *VerifyResult) OK() bool {
+	return r.SourceRowCount == r.DestinationRowCount && len(r.Mismatches) == 0
+}
+
+// VerifyCopy - compare the row counts and the checksums of the rows between the source and the
+// destination tables after a copy or migration. The source rows are exported and each compared
+// row is queried from the destination by the primary key, the vector fields are compared as well.
+//
+// PARAMS:
+//   - ctx: the context to cancel the verification
+//   - source: the source table
+//   - destination: the destination table
+//   - options: the options of the verification, nil means comparing all the rows
+//
+// RETURNS:
+//   - *VerifyResult: the result of the verification
+//   - error: nil if ok otherwise the specific error
+func VerifyCopy(ctx context.Context, source, destination TableRef,
+	options *VerifyOptions) (*VerifyResult, error) {
+	if options == nil {
+		options = &VerifyOptions{}
+	}
+	maxMismatches := options.MaxMismatches
+	if maxMismatches <= 0 {
+		maxMismatches = DefaultVerifyMaxMismatches
+	}
+	source.Client = source.Client.WithContext(ctx)
+	destination.Client = destination.Client.WithContext(ctx)
+	result := &VerifyResult{}
+	srcStats, err := source.Client.ShowTableStats(source.Database, source.Table)
+	if err != nil {
+		return nil, err
+	}
+	dstStats, err := destination.Client.ShowTableStats(destination.Database, destination.Table)
+	if err != nil {
+		return nil, err
+	}
+	result.SourceRowCount = srcStats.RowCount
+	result.DestinationRowCount = dstStats.RowCount
+
+	primaryKey, partitionKey, err := source.Client.tableKeys(source.Database, source.Table)
+	if err != nil {
+		return nil, err
+	}
+	it := source.Client.ExportRows(ctx, source.Database, source.Table, options.Filter,
+		&ExportOptions{BatchSize: options.BatchSize, ReadConsistency: api.STRONG,
+			RetrieveVector: true})
+	for it.Next() {
+		row := it.Row()
+		pk := pickFields(row, primaryKey)
+		if options.SampleRate > 0 && options.SampleRate < 1 &&
+			float64(RowChecksum(api.Row{Fields: pk})) > options.SampleRate*math.MaxUint64 {
+			continue
+		}
+		args := &api.QueryRowArgs{
+			Database:        destination.Database,
+			Table:           destination.Table,
+			PrimaryKey:      pk,
+			ReadConsistency: api.STRONG,
+			RetrieveVector:  true,
+		}
+		if len(partitionKey) != 0 {
+			args.PartitionKey = pickFields(row, partitionKey)
+		}
+		dst, err := destination.Client.QueryRow(args)
+		if err != nil {
+			return nil, err
+		}
+		result.ComparedRows++
+		switch {
+		case len(dst.Row.Fields) == 0:
+			result.Mismatches = append(result.Mismatches, Mismatch{pk, MismatchMissing})
+		case RowChecksum(row) != RowChecksum(dst.Row):
+			result.Mismatches = append(result.Mismatches, Mismatch{pk, MismatchDifferent})
+		}
+		if len(result.Mismatches) >= maxMismatches {
+			result.Truncated = true
+			return result, nil
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RowChecksum - compute the checksum of the row which is independent of the order of the fields
+func RowChecksum(row api.Row) uint64 {
+	h := fnv.New64a()
+	writeChecksumValue(h, row.Fields)
+	return h.Sum64()
+}
+
+func writeChecksumValue(w io.Writer, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		io.WriteString(w, "{")
+		for _, key := range keys {
+			fmt.Fprintf(w, "%q:", key)
+			writeChecksumValue(w, v[key])
+			io.WriteString(w, ",")
+		}
+		io.WriteString(w, "}")
+	case []interface{}:
+		io.WriteString(w, "[")
+		for _, item := range v {
+			writeChecksumValue(w, item)
+			io.WriteString(w, ",")
+		}
+		io.WriteString(w, "]")
+	case string:
+		fmt.Fprintf(w, "%q", v)
+	default:
+		fmt.Fprintf(w, "%v", v)
+	}
+}
@@ -0,0 +1,233 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// wait.go - the waiters of the state transitions of the tables and indexes
+
+package mochow
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/baidu/mochow-sdk-go/mochow/api"
+)
+
+const (
+	DefaultWaitPollInterval    = time.Second
+	DefaultWaitMaxPollInterval = 30 * time.Second
+	// DefaultTaskWaitBackoff is the backoff of WaitForTask if the options are nil
+	DefaultTaskWaitBackoff = 2
+)
+
+// TaskFailedError is returned by WaitForTask if the task failed or was canceled
+type TaskFailedError struct {
+	Task *api.Task
+}
+
+func (e *TaskFailedError) Error() string {
+	if len(e.Task.Message) == 0 {
+		return fmt.Sprintf("task %s %s", e.Task.TaskID, e.Task.State)
+	}
+	return fmt.Sprintf("task %s %s: %s", e.Task.TaskID, e.Task.State, e.Task.Message)
+}
+
+// WaitOptions defines the options of the waiters
+type WaitOptions struct {
+	// PollInterval is the interval of the first check, use DefaultWaitPollInterval if not positive
+	PollInterval time.Duration
+	// Backoff multiplies the interval after each check, the interval is constant if not greater
+	// than 1
+	Backoff float64
+	// MaxPollInterval caps the interval grown by the backoff, use DefaultWaitMaxPollInterval if
+	// not positive
+	MaxPollInterval time.Duration
+	// Timeout limits the total time of the waiting, no limit other than the context if not positive
+	Timeout time.Duration
+}
+
+// poll - call check until it reports done or fails, sleeping between the calls as the options,
+// the ctx passed to check is bounded by the Timeout as well so that a hanging request does not
+// outlive it
+func poll(ctx context.Context, options *WaitOptions,
+	check func(ctx context.Context) (bool, error)) error {
+	if options == nil {
+		options = &WaitOptions{}
+	}
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+	interval := options.PollInterval
+	if interval <= 0 {
+		interval = DefaultWaitPollInterval
+	}
+	maxInterval := options.MaxPollInterval
+	if maxInterval <= 0 {
+		maxInterval = DefaultWaitMaxPollInterval
+	}
+	for {
+		done, err := check(ctx)
+		if err != nil || done {
+			return err
+		}
+		if err := sleepContext(ctx, interval); err != nil {
+			return err
+		}
+		if options.Backoff > 1 {
+			interval = time.Duration(float64(interval) * options.Backoff)
+			if interval > maxInterval {
+				interval = maxInterval
+			}
+		}
+	}
+}
+
+// sleepContext - sleep for the duration unless the context is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WaitForTableReady - wait until the state of the table is NORMAL
+//
+// PARAMS:
+//   - ctx: the context to cancel the requests and the waiting
+//   - database: the database name
+//   - table: the table name
+//   - options: the options of the waiting, nil means default
+//
+// RETURNS:
+//   - error: nil if ok, the error of the context if canceled or timed out, otherwise the
+//     specific error
+func (c *Client) WaitForTableReady(ctx context.Context, database, table string,
+	options *WaitOptions) error {
+	return poll(ctx, options, func(ctx context.Context) (bool, error) {
+		result, err := c.WithContext(ctx).DescTable(database, table)
+		if err != nil {
+			return false, err
+		}
+		return result.Table != nil && result.Table.State == api.TableStateNormal, nil
+	})
+}
+
+// WaitForTableDropped - wait until the table does not exist
+//
+// PARAMS:
+//   - ctx: the context to cancel the requests and the waiting
+//   - database: the database name
+//   - table: the table name
+//   - options: the options of the waiting, nil means default
+//
+// RETURNS:
+//   - error: nil if ok, the error of the context if canceled or timed out, otherwise the
+//     specific error
+func (c *Client) WaitForTableDropped(ctx context.Context, database, table string,
+	options *WaitOptions) error {
+	return poll(ctx, options, func(ctx context.Context) (bool, error) {
+		_, err := c.WithContext(ctx).DescTable(database, table)
+		if api.IsTableNotExist(err) || api.IsDatabaseNotExist(err) {
+			return true, nil
+		}
+		return false, err
+	})
+}
+
+// WaitForIndexReady - wait until the state of the index is NORMAL, e.g. after the rebuilding
+//
+// PARAMS:
+//   - ctx: the context to cancel the requests and the waiting
+//   - database: the database name
+//   - table: the table name
+//   - index: the index name
+//   - options: the options of the waiting, nil means default
+//
+// RETURNS:
+//   - error: nil if ok, the error of the context if canceled or timed out, otherwise the
+//     specific error
+func (c *Client) WaitForIndexReady(ctx context.Context, database, table, index string,
+	options *WaitOptions) error {
+	return poll(ctx, options, func(ctx context.Context) (bool, error) {
+		result, err := c.WithContext(ctx).DescIndex(database, table, index)
+		if err != nil {
+			return false, err
+		}
+		return result.Index.State == api.IndexStateNormal, nil
+	})
+}
+
+// WaitForIndexDropped - wait until the index does not exist
+//
+// PARAMS:
+//   - ctx: the context to cancel the requests and the waiting
+//   - database: the database name
+//   - table: the table name
+//   - index: the index name
+//   - options: the options of the waiting, nil means default
+//
+// RETURNS:
+//   - error: nil if ok, the error of the context if canceled or timed out, otherwise the
+//     specific error
+func (c *Client) WaitForIndexDropped(ctx context.Context, database, table, index string,
+	options *WaitOptions) error {
+	return poll(ctx, options, func(ctx context.Context) (bool, error) {
+		_, err := c.WithContext(ctx).DescIndex(database, table, index)
+		if api.IsIndexNotExist(err) {
+			return true, nil
+		}
+		return false, err
+	})
+}
+
+// WaitForTask - wait until the background task is done, e.g. the task of an index rebuilding or
+// a backup, the interval grows by DefaultTaskWaitBackoff if the options are nil
+//
+// PARAMS:
+//   - ctx: the context to cancel the requests and the waiting
+//   - taskID: the task id
+//   - options: the options of the waiting, nil means default
+//
+// RETURNS:
+//   - *api.Task: the last described task, it is set even if the waiting fails
+//   - error: nil if the task succeeded, *TaskFailedError if it failed or was canceled, the error
+//     of the context if canceled or timed out, otherwise the specific error
+func (c *Client) WaitForTask(ctx context.Context, taskID string,
+	options *WaitOptions) (*api.Task, error) {
+	if options == nil {
+		options = &WaitOptions{Backoff: DefaultTaskWaitBackoff}
+	}
+	var task *api.Task
+	err := poll(ctx, options, func(ctx context.Context) (bool, error) {
+		described, err := c.WithContext(ctx).DescTask(taskID)
+		if err != nil {
+			return false, err
+		}
+		task = described
+		return task.IsDone(), nil
+	})
+	if err != nil {
+		return task, err
+	}
+	if task.State != api.TaskStateSucceeded {
+		return task, &TaskFailedError{Task: task}
+	}
+	return task, nil
+}
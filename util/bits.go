@@ -0,0 +1,52 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// bits.go - define the packing utility functions of the binary vectors
+
+package util
+
+// PackBits - pack the bits into the bytes of the binary vector, the first bit is the most
+// significant bit of the first byte and the last byte is padded with zeros, e.g. a vector of
+// dimension 16 is packed into 2 bytes. The packed bytes are the value of the BINARY_VECTOR field
+// in the rows and the query vector of the binary ann search.
+func PackBits(bits []bool) []byte {
+	packed := make([]byte, (len(bits)+7)/8)
+	for i, bit := range bits {
+		if bit {
+			packed[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return packed
+}
+
+// PackBitBytes - pack the bitset of one byte per bit like PackBits, the non-zero bytes are 1
+func PackBitBytes(bits []byte) []byte {
+	packed := make([]byte, (len(bits)+7)/8)
+	for i, bit := range bits {
+		if bit != 0 {
+			packed[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return packed
+}
+
+// UnpackBits - unpack the first dimension bits of the packed binary vector, the missing bits are
+// false
+func UnpackBits(packed []byte, dimension int) []bool {
+	bits := make([]bool, dimension)
+	for i := 0; i < dimension && i/8 < len(packed); i++ {
+		bits[i] = packed[i/8]&(0x80>>(i%8)) != 0
+	}
+	return bits
+}
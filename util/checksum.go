@@ -0,0 +1,47 @@
+/*
+ * Copyright 2024 Baidu, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+ * except in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the
+ * License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions
+ * and limitations under the License.
+ */
+
+// checksum.go - define the checksum utility functions of the request and response bodies
+
+package util
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"hash/crc32"
+	"io"
+	"strconv"
+)
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// ContentMD5 - get the base64 encoded md5 digest of the content, i.e. the Content-MD5 header value
+func ContentMD5(content []byte) string {
+	sum := md5.Sum(content)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// ContentMD5FromReader - get the base64 encoded md5 digest of all the data read from the reader
+func ContentMD5FromReader(r io.Reader) (string, error) {
+	hash := md5.New()
+	if _, err := io.Copy(hash, r); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil)), nil
+}
+
+// ContentCRC32C - get the decimal CRC32C(Castagnoli) checksum of the content
+func ContentCRC32C(content []byte) string {
+	return strconv.FormatUint(uint64(crc32.Checksum(content, crc32cTable)), 10)
+}